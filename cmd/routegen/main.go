@@ -0,0 +1,456 @@
+// Command routegen writes zz_routes.go for a handler package: a RouteBindings
+// method per handler type returning a binding per route method, so AutoRouter
+// can call the method on any instance, e.g. one built for the request,
+// without reflect.Value.Call. Both func(*gin.Context) methods and typed
+// func(context.Context[, *Request]) ([*Response, ]error) methods are bound.
+//
+// Usage, from a handler file, with the base path and singletons the module
+// mounts the handlers with, so comments show the routes the router derives:
+//
+//	//go:generate go run study-go-controller/cmd/routegen -base /users -singletons Profile
+//
+// With -check, routegen exits non-zero when zz_routes.go is missing or stale.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"study-go-controller/pkg/routing"
+)
+
+const (
+	outputFile  = "zz_routes.go"
+	ginPath     = "github.com/gin-gonic/gin"
+	contextPath = "context"
+	routingPath = "study-go-controller/pkg/routing"
+)
+
+// routeConstructors are the routing functions that declare a method and path
+var routeConstructors = map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+
+// versionSuffix strips API version markers from handler names, e.g. PostV2 -> Post
+var versionSuffix = regexp.MustCompile(`V\d+$`)
+
+// handlerType collects the routable methods of one handler type
+type handlerType struct {
+	Name     string
+	Methods  []routeMethod
+	Declared map[string]bool // methods named in the handler's Routes()
+}
+
+// routeMethod is a method routegen binds
+type routeMethod struct {
+	Name string
+	// Typed is set for func(context.Context[, *Request]) ([*Response, ]error) methods
+	Typed       bool
+	Request     string // the request type as written, e.g. "*dto.CreatePostRequest"; "" without one
+	HasResponse bool
+}
+
+func main() {
+	dir := flag.String("dir", ".", "handler package directory")
+	check := flag.Bool("check", false, "fail if "+outputFile+" is missing or out of date instead of writing it")
+	base := flag.String("base", "", "base path the handlers are mounted at, e.g. /users")
+	singletons := flag.String("singletons", "", "comma-separated singleton sub-resources, see container.WithSingletons")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("routegen: ")
+
+	mount := routing.ConventionContext{BasePath: *base}
+	for _, name := range strings.Split(*singletons, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			mount.Singletons = append(mount.Singletons, name)
+		}
+	}
+
+	source, err := generate(*dir, mount)
+	if err != nil {
+		log.Fatal(err)
+	}
+	path := filepath.Join(*dir, outputFile)
+
+	if *check {
+		current, err := os.ReadFile(path)
+		if os.IsNotExist(err) && source == nil {
+			return
+		}
+		if err != nil || !bytes.Equal(current, source) {
+			log.Fatalf("%s is out of date; run go generate ./...", path)
+		}
+		return
+	}
+
+	if source == nil {
+		// Nothing to bind; don't leave a stale file behind
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := os.WriteFile(path, source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate parses the package in dir and renders zz_routes.go, or returns
+// nil when no handler type has route methods. mount holds the BasePath and
+// Singletons the handlers are mounted with.
+func generate(dir string, mount routing.ConventionContext) ([]byte, error) {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != outputFile
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(packages) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(packages))
+	}
+
+	var pkg *ast.Package
+	for _, p := range packages {
+		pkg = p
+	}
+
+	handlers := make(map[string]*handlerType)
+	imports := newImportSet()
+	for _, file := range pkg.Files {
+		ginName := importName(file, ginPath)
+		contextName := importName(file, contextPath)
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil {
+				continue
+			}
+			receiver := receiverName(fn)
+			if receiver == "" {
+				continue
+			}
+
+			handler, ok := handlers[receiver]
+			if !ok {
+				handler = &handlerType{Name: receiver, Declared: make(map[string]bool)}
+				handlers[receiver] = handler
+			}
+			if fn.Name.Name == "Routes" {
+				collectDeclared(fn, handler.Declared)
+			}
+			if !fn.Name.IsExported() {
+				continue
+			}
+			if ginName != "" && isGinHandler(fn.Type, ginName) {
+				handler.Methods = append(handler.Methods, routeMethod{Name: fn.Name.Name})
+				if err := imports.add("", ginPath); err != nil {
+					return nil, err
+				}
+			}
+			if contextName != "" && isTypedHandler(fn.Type, contextName) {
+				method, err := typedMethod(fset, file, fn, imports)
+				if err != nil {
+					return nil, err
+				}
+				handler.Methods = append(handler.Methods, method)
+				if err := imports.add("", contextPath); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	var names []string
+	for name, handler := range handlers {
+		if len(handler.Methods) > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	sort.Strings(names)
+
+	if err := imports.add("", routingPath); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by routegen. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+	imports.write(&buf)
+	for _, name := range names {
+		writeBindings(&buf, handlers[name], mount)
+	}
+	return format.Source(buf.Bytes())
+}
+
+// writeBindings renders the RouteBindings method of one handler type,
+// commenting each method with the route AutoRouter derives for it
+func writeBindings(buf *bytes.Buffer, handler *handlerType, mount routing.ConventionContext) {
+	sort.Slice(handler.Methods, func(i, j int) bool { return handler.Methods[i].Name < handler.Methods[j].Name })
+	// The same context AutoRouter.RegisterHandler builds
+	ctx := mount
+	ctx.HandlerName = handler.Name
+	ctx.Resource = routing.ResourceName(mount.BasePath)
+	if ctx.Resource == "" {
+		ctx.Resource = versionSuffix.ReplaceAllString(strings.TrimSuffix(handler.Name, "Handler"), "")
+	}
+
+	fmt.Fprintf(buf, "\n// RouteBindings implements routing.BindingProvider\n")
+	fmt.Fprintf(buf, "func (*%s) RouteBindings() map[string]routing.Binding {\n", handler.Name)
+	fmt.Fprintf(buf, "return map[string]routing.Binding{\n")
+	for _, method := range handler.Methods {
+		var comment string
+		if handler.Declared[method.Name] {
+			comment = " // declared in Routes()"
+		} else if httpMethod, path, ok := (routing.RESTConvention{}).Route(ctx, method.Name); ok {
+			comment = fmt.Sprintf(" // %s %s by convention", httpMethod, mount.BasePath+path)
+		}
+
+		if !method.Typed {
+			fmt.Fprintf(buf, "%q: {Gin: func(h interface{}, c *gin.Context) { h.(*%s).%s(c) }},%s\n",
+				method.Name, handler.Name, method.Name, comment)
+			continue
+		}
+
+		fmt.Fprintf(buf, "%q: {%s\n", method.Name, comment)
+		args := "ctx"
+		if method.Request != "" {
+			args = fmt.Sprintf("ctx, req.(%s)", method.Request)
+		}
+		fmt.Fprintf(buf, "Typed: func(h interface{}, ctx context.Context, req interface{}) (interface{}, error) {\n")
+		if method.HasResponse {
+			fmt.Fprintf(buf, "resp, err := h.(*%s).%s(%s)\nreturn resp, err\n", handler.Name, method.Name, args)
+		} else {
+			fmt.Fprintf(buf, "return nil, h.(*%s).%s(%s)\n", handler.Name, method.Name, args)
+		}
+		fmt.Fprintf(buf, "},\n")
+		if method.Request != "" {
+			fmt.Fprintf(buf, "NewRequest: func() interface{} { return new(%s) },\n", strings.TrimPrefix(method.Request, "*"))
+		}
+		fmt.Fprintf(buf, "},\n")
+	}
+	fmt.Fprintf(buf, "}\n}\n")
+}
+
+// importName returns the name a file uses for an import path, or "" if not imported
+func importName(file *ast.File, path string) string {
+	for _, spec := range file.Imports {
+		if value, _ := strconv.Unquote(spec.Path.Value); value == path {
+			if spec.Name != nil {
+				return spec.Name.Name
+			}
+			return filepath.Base(path)
+		}
+	}
+	return ""
+}
+
+// receiverName returns the type name of a method receiver, T or *T
+func receiverName(fn *ast.FuncDecl) string {
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// isGinHandler reports whether a method is shaped like func(*gin.Context)
+func isGinHandler(fn *ast.FuncType, ginName string) bool {
+	if fn.Results != nil && len(fn.Results.List) > 0 {
+		return false
+	}
+	if fn.Params == nil || len(fn.Params.List) != 1 || len(fn.Params.List[0].Names) > 1 {
+		return false
+	}
+
+	star, ok := fn.Params.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := star.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && pkg.Name == ginName && selector.Sel.Name == "Context"
+}
+
+// isTypedHandler reports whether a method is shaped like
+// func(context.Context[, *Request]) ([*Response, ]error)
+func isTypedHandler(fn *ast.FuncType, contextName string) bool {
+	params, results := fieldTypes(fn.Params), fieldTypes(fn.Results)
+	if len(params) < 1 || len(params) > 2 || len(results) < 1 || len(results) > 2 {
+		return false
+	}
+
+	selector, ok := params[0].(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if pkg, ok := selector.X.(*ast.Ident); !ok || pkg.Name != contextName || selector.Sel.Name != "Context" {
+		return false
+	}
+	if len(params) == 2 {
+		if _, ok := params[1].(*ast.StarExpr); !ok {
+			return false
+		}
+	}
+
+	last, ok := results[len(results)-1].(*ast.Ident)
+	return ok && last.Name == "error"
+}
+
+// typedMethod describes a typed handler method, adding the imports its
+// request type needs to imports
+func typedMethod(fset *token.FileSet, file *ast.File, fn *ast.FuncDecl, imports *importSet) (routeMethod, error) {
+	params := fieldTypes(fn.Type.Params)
+	method := routeMethod{
+		Name:        fn.Name.Name,
+		Typed:       true,
+		HasResponse: len(fieldTypes(fn.Type.Results)) == 2,
+	}
+	if len(params) < 2 {
+		return method, nil
+	}
+
+	var request bytes.Buffer
+	if err := format.Node(&request, fset, params[1]); err != nil {
+		return method, err
+	}
+	method.Request = request.String()
+
+	// Qualified identifiers such as dto.CreatePostRequest need their import
+	var err error
+	ast.Inspect(params[1], func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+		if pkg, ok := selector.X.(*ast.Ident); ok {
+			name, path := importOf(file, pkg.Name)
+			if path == "" {
+				err = fmt.Errorf("%s.%s: no import for %s", fn.Name.Name, method.Request, pkg.Name)
+				return false
+			}
+			err = imports.add(name, path)
+		}
+		return false
+	})
+	return method, err
+}
+
+// fieldTypes lists the type of every parameter or result, once per name
+func fieldTypes(fields *ast.FieldList) []ast.Expr {
+	if fields == nil {
+		return nil
+	}
+	var types []ast.Expr
+	for _, field := range fields.List {
+		for n := 0; n < len(field.Names) || n == 0; n++ {
+			types = append(types, field.Type)
+		}
+	}
+	return types
+}
+
+// importOf returns the explicit name ("" if none) and path of the import a
+// file refers to as name
+func importOf(file *ast.File, name string) (string, string) {
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if spec.Name != nil {
+			if spec.Name.Name == name {
+				return name, path
+			}
+			continue
+		}
+		if filepath.Base(path) == name {
+			return "", path
+		}
+	}
+	return "", ""
+}
+
+// importSet collects the imports of the generated file
+type importSet struct {
+	names map[string]string // path -> explicit name, "" for none
+}
+
+func newImportSet() *importSet {
+	return &importSet{names: make(map[string]string)}
+}
+
+// add records an import, rejecting one path imported under two names
+func (s *importSet) add(name, path string) error {
+	if existing, ok := s.names[path]; ok && existing != name {
+		return fmt.Errorf("%s is imported both as %q and %q", path, existing, name)
+	}
+	s.names[path] = name
+	return nil
+}
+
+// write renders the import block like the rest of the repo: standard
+// library and module packages first, then third-party ones
+func (s *importSet) write(buf *bytes.Buffer) {
+	var local, external []string
+	for path := range s.names {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			external = append(external, path)
+		} else {
+			local = append(local, path)
+		}
+	}
+	sort.Strings(local)
+	sort.Strings(external)
+
+	buf.WriteString("import (\n")
+	for i, group := range [][]string{local, external} {
+		if i > 0 && len(local) > 0 && len(group) > 0 {
+			buf.WriteString("\n")
+		}
+		for _, path := range group {
+			if name := s.names[path]; name != "" {
+				fmt.Fprintf(buf, "%s ", name)
+			}
+			fmt.Fprintf(buf, "%q\n", path)
+		}
+	}
+	buf.WriteString(")\n")
+}
+
+// collectDeclared records the methods Routes() gives an explicit method and
+// path, e.g. routing.GET("/by-author", "GetPostsByAuthor")
+func collectDeclared(fn *ast.FuncDecl, declared map[string]bool) {
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !routeConstructors[selector.Sel.Name] {
+			return true
+		}
+		for _, arg := range call.Args {
+			if lit, ok := arg.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if value, err := strconv.Unquote(lit.Value); err == nil {
+					declared[value] = true
+				}
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"study-go-controller/pkg/routing"
+	"testing"
+)
+
+// handlerMounts are the contexts of the go:generate directives
+var handlerMounts = map[string]routing.ConventionContext{
+	"../../internal/domain/post/handler": {BasePath: "/posts"},
+	"../../internal/domain/user/handler": {BasePath: "/users", Singletons: []string{"Profile"}},
+}
+
+func TestGeneratedBindingsAreUpToDate(t *testing.T) {
+	for dir, mount := range handlerMounts {
+		source, err := generate(dir, mount)
+		if err != nil {
+			t.Fatalf("%s: %v", dir, err)
+		}
+		current, err := os.ReadFile(filepath.Join(dir, outputFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(current, source) {
+			t.Errorf("%s/%s is out of date; run go generate ./...", dir, outputFile)
+		}
+	}
+}
+
+func TestTypedMethodsGetBindings(t *testing.T) {
+	dir := "../../internal/domain/post/handler"
+	source, err := generate(dir, handlerMounts[dir])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`resp, err := h.(*PostHandler).CreatePost(ctx, req.(*dto.CreatePostRequest))`,
+		`NewRequest: func() interface{} { return new(dto.CreatePostRequest) }`,
+		`"study-go-controller/internal/domain/post/dto"`,
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("generated bindings lack %s", want)
+		}
+	}
+}
+
+func TestConventionCommentsUseTheMountContext(t *testing.T) {
+	dir := "../../internal/domain/user/handler"
+	tests := []struct {
+		name  string
+		mount routing.ConventionContext
+		want  string
+	}{
+		{"mounted", handlerMounts[dir], `// GET /users/:id/profile by convention`},
+		{"without singletons", routing.ConventionContext{BasePath: "/users"}, `// GET /users/:id/profiles/:profileId by convention`},
+		{"without base path", routing.ConventionContext{}, `// POST /batch-create by convention`},
+	}
+	for _, tt := range tests {
+		source, err := generate(dir, tt.mount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(source), tt.want) {
+			t.Errorf("%s: generated bindings lack %s", tt.name, tt.want)
+		}
+	}
+}
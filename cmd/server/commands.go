@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"study-go-controller/pkg/config"
+	"study-go-controller/pkg/container"
+	"study-go-controller/pkg/database"
+	"study-go-controller/pkg/migrate"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// runCommand runs a CLI subcommand and reports whether one was given
+func runCommand(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	// Commands see the same .env configuration as the server
+	if err := godotenv.Load(); err != nil {
+		fmt.Fprintln(os.Stderr, "No .env file found")
+	}
+
+	switch args[0] {
+	case "openapi":
+		return true, openAPICommand(args[1:])
+	case "routes":
+		return true, routesCommand(args[1:])
+	case "graph":
+		return true, graphCommand(args[1:])
+	case "migrate":
+		return true, migrateCommand(args[1:])
+	default:
+		return true, fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// openAPICommand writes the OpenAPI document to stdout or a file
+func openAPICommand(args []string) error {
+	flags := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	output := flags.String("o", "", "write the document to this file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// The route table doesn't need a database connection
+	c, err := container.NewRouteContainer()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c.OpenAPI())
+}
+
+// routesCommand prints the route catalog as a text table or JSON
+func routesCommand(args []string) error {
+	flags := flag.NewFlagSet("routes", flag.ContinueOnError)
+	format := flags.String("format", "text", "output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := container.NewRouteContainer()
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		return container.WriteRouteTable(os.Stdout, c.RouteCatalog())
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(c.RouteCatalog())
+	default:
+		return fmt.Errorf("unknown format %q (want text or json)", *format)
+	}
+}
+
+// graphCommand prints the dependency graph and fails when it has issues
+func graphCommand(args []string) error {
+	flags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	format := flags.String("format", "dot", "output format: dot, mermaid or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := container.NewRouteContainer()
+	if err != nil {
+		return err
+	}
+	graph := c.DependencyGraph()
+
+	switch *format {
+	case "dot":
+		err = graph.WriteDOT(os.Stdout)
+	case "mermaid":
+		err = graph.WriteMermaid(os.Stdout)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(graph)
+	default:
+		return fmt.Errorf("unknown format %q (want dot, mermaid or json)", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range graph.Issues {
+		fmt.Fprintf(os.Stderr, "⚠️ %s: %s\n", issue.Kind, issue.Message)
+	}
+	if len(graph.Issues) > 0 {
+		return fmt.Errorf("dependency graph has %d issue(s)", len(graph.Issues))
+	}
+	return nil
+}
+
+// migrateUsage lists the migrate subcommands
+const migrateUsage = "usage: migrate up | down [-steps n] | status | redo | unlock | create -dir <dir> <name>"
+
+// migrateCommand applies, reverts, lists or creates migrations of the enabled modules
+func migrateCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	if args[0] == "create" {
+		return migrateCreateCommand(args[1:])
+	}
+
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	steps := flags.Int("steps", 1, "number of migrations to revert (down)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg := config.Load()
+	modules, err := container.EnabledModules(cfg.DisabledModules)
+	if err != nil {
+		return err
+	}
+	db, err := database.NewDatabase(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Only the migrator's own log lines, not every statement
+	quiet := db.DB.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Warn)})
+	migrator, err := container.NewMigrator(quiet, modules)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		fmt.Printf("%d migration(s) applied\n", applied)
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, *steps)
+		fmt.Printf("%d migration(s) reverted\n", reverted)
+		return err
+	case "redo":
+		return migrator.Redo(ctx)
+	case "unlock":
+		return migrator.Unlock(ctx)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return writeMigrationStatus(os.Stdout, statuses)
+	default:
+		return fmt.Errorf("unknown migrate command %q; %s", args[0], migrateUsage)
+	}
+}
+
+// migrateCreateCommand writes empty up and down SQL files for a new migration
+func migrateCreateCommand(args []string) error {
+	flags := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+	dir := flags.String("dir", "", "directory of the module's migrations, e.g. internal/domain/post/migrations")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" || flags.NArg() != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	paths, err := migrate.Create(*dir, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		fmt.Println("📝 Created", path)
+	}
+	return nil
+}
+
+// writeMigrationStatus prints one line per migration
+func writeMigrationStatus(w io.Writer, statuses []migrate.Status) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", "-"
+		if status.Applied {
+			state, appliedAt = "applied", status.AppliedAt.Local().Format(time.DateTime)
+		}
+		switch {
+		case status.Dirty:
+			state += " (dirty)"
+		case status.Modified:
+			state += " (modified)"
+		case status.Unknown:
+			state += " (unknown)"
+		case status.Down == nil:
+			state += " (irreversible)"
+		}
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return table.Flush()
+}
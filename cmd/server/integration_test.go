@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	postDto "study-go-controller/internal/domain/post/dto"
+	userDto "study-go-controller/internal/domain/user/dto"
+	"study-go-controller/pkg/batch"
+	"study-go-controller/pkg/config"
+	"study-go-controller/pkg/container"
+	"study-go-controller/pkg/database"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// apiResponse mirrors response.APIResponse with typed data
+type apiResponse[T any] struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+	Error   string `json:"error"`
+}
+
+// newTestServer assembles the full router of the enabled modules on a
+// fresh in-memory SQLite database, migrated to the latest version
+func newTestServer(t *testing.T, opts ...container.Option) *gin.Engine {
+	t.Helper()
+	db, err := database.NewDatabase(config.DatabaseConfig{Driver: database.DriverSQLite, Name: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	quiet := db.DB.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+
+	cfg := &config.Config{MigrateOnStart: container.MigrateUp}
+	c, err := container.NewContainer(append([]container.Option{
+		container.WithConfig(cfg),
+		container.WithDB(quiet),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Stop(context.Background()) })
+
+	router := gin.New()
+	c.RegisterRoutes(router)
+	return router
+}
+
+// call sends a JSON request and decodes the response into T
+func call[T any](t *testing.T, router http.Handler, method, path string, body interface{}, headers ...string) (int, apiResponse[T]) {
+	t.Helper()
+	var reader bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reader).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &reader)
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	var resp apiResponse[T]
+	if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s %s: decoding %q: %v", method, path, recorder.Body, err)
+	}
+	return recorder.Code, resp
+}
+
+// createUser creates a user named name and returns it
+func createUser(t *testing.T, router http.Handler, name string) userDto.UserResponse {
+	t.Helper()
+	status, resp := call[userDto.UserResponse](t, router, http.MethodPost, "/api/v1/users", userDto.CreateUserRequest{
+		Username: name, Email: name + "@example.com", Password: "secret1", Name: name,
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("creating user %s = %d %s", name, status, resp.Error)
+	}
+	return resp.Data
+}
+
+// login signs in as the user created by createUser and returns the
+// Authorization header pair for call
+func login(t *testing.T, router http.Handler, name string) []string {
+	t.Helper()
+	status, resp := call[userDto.LoginResponse](t, router, http.MethodPost, "/api/v1/users/login", userDto.LoginRequest{
+		Email: name + "@example.com", Password: "secret1",
+	})
+	if status != http.StatusOK || resp.Data.Token == "" {
+		t.Fatalf("logging in as %s = %d %s", name, status, resp.Error)
+	}
+	return []string{"Authorization", "Bearer " + resp.Data.Token}
+}
+
+func TestUserCreateAndGet(t *testing.T) {
+	router := newTestServer(t)
+	alice := createUser(t, router, "alice")
+
+	status, resp := call[userDto.UserResponse](t, router, http.MethodGet, fmt.Sprintf("/api/v1/users/%d", alice.ID), nil)
+	if status != http.StatusOK || resp.Data.Username != "alice" || resp.Data.Email != "alice@example.com" {
+		t.Fatalf("GET user = %d %+v", status, resp)
+	}
+
+	status, resp = call[userDto.UserResponse](t, router, http.MethodPost, "/api/v1/users", userDto.CreateUserRequest{
+		Username: "alice", Email: "other@example.com", Password: "secret1", Name: "alice",
+	})
+	if status != http.StatusBadRequest || resp.Error != "user with this username already exists" {
+		t.Fatalf("creating a duplicate username = %d %+v, want 400", status, resp)
+	}
+
+	status, _ = call[userDto.UserResponse](t, router, http.MethodGet, "/api/v1/users/999", nil)
+	if status != http.StatusNotFound {
+		t.Fatalf("GET unknown user = %d, want 404", status)
+	}
+}
+
+func TestPostCreateAndGetWithAuthor(t *testing.T) {
+	router := newTestServer(t)
+	alice := createUser(t, router, "alice")
+
+	status, created := call[postDto.PostResponse](t, router, http.MethodPost, "/api/v1/posts", postDto.CreatePostRequest{
+		Title: "Hello", Content: "First post", AuthorID: alice.ID,
+	})
+	if status != http.StatusCreated || created.Data.Author == nil || created.Data.Author.Username != "alice" {
+		t.Fatalf("POST post = %d %+v", status, created)
+	}
+
+	status, fetched := call[postDto.PostResponse](t, router, http.MethodGet, fmt.Sprintf("/api/v1/posts/%d", created.Data.ID), nil)
+	if status != http.StatusOK || fetched.Data.Title != "Hello" || fetched.Data.Author == nil || fetched.Data.Author.ID != alice.ID {
+		t.Fatalf("GET post = %d %+v", status, fetched)
+	}
+
+	status, _ = call[postDto.PostResponse](t, router, http.MethodPost, "/api/v1/posts", postDto.CreatePostRequest{
+		Title: "Orphan", AuthorID: 999,
+	})
+	if status != http.StatusBadRequest {
+		t.Fatalf("POST post with unknown author = %d, want 400", status)
+	}
+}
+
+func TestBatchEndpoints(t *testing.T) {
+	router := newTestServer(t)
+	user := func(name string) userDto.CreateUserRequest {
+		return userDto.CreateUserRequest{Username: name, Email: name + "@example.com", Password: "secret1", Name: name}
+	}
+
+	// Atomic: the duplicate rolls back the whole batch
+	status, resp := call[batch.Result](t, router, http.MethodPost, "/api/v1/users/batch-create", userDto.BatchCreateUsersRequest{
+		Items: []userDto.CreateUserRequest{user("alice"), user("alice")},
+	})
+	if status != http.StatusBadRequest || resp.Data.Failed != 2 || resp.Data.Items[0].Status != http.StatusFailedDependency {
+		t.Fatalf("atomic batch with a duplicate = %d %+v", status, resp.Data)
+	}
+	status, users := call[[]userDto.UserResponse](t, router, http.MethodGet, "/api/v1/users", nil)
+	if status != http.StatusOK || len(users.Data) != 0 {
+		t.Fatalf("users after rolled back batch = %d %+v", status, users.Data)
+	}
+
+	// Best effort: the duplicate fails alone
+	best := userDto.BatchCreateUsersRequest{Items: []userDto.CreateUserRequest{user("alice"), user("alice"), user("bob")}}
+	best.Mode = batch.BestEffort
+	status, resp = call[batch.Result](t, router, http.MethodPost, "/api/v1/users/batch-create", best)
+	if status != http.StatusMultiStatus || resp.Data.Succeeded != 2 || resp.Data.Items[1].Status != http.StatusBadRequest {
+		t.Fatalf("best-effort batch with a duplicate = %d %+v", status, resp.Data)
+	}
+	status, users = call[[]userDto.UserResponse](t, router, http.MethodGet, "/api/v1/users", nil)
+	if status != http.StatusOK || len(users.Data) != 2 {
+		t.Fatalf("users after best-effort batch = %d %+v", status, users.Data)
+	}
+	alice := users.Data[0]
+
+	// Posts: an unknown author fails its own item only
+	posts := postDto.BatchCreatePostsRequest{Items: []postDto.CreatePostRequest{
+		{Title: "One", AuthorID: alice.ID},
+		{Title: "Orphan", AuthorID: 999},
+		{Title: "Two", AuthorID: alice.ID},
+	}}
+	posts.Mode = batch.BestEffort
+	status, resp = call[batch.Result](t, router, http.MethodPost, "/api/v1/posts/batch-create", posts)
+	if status != http.StatusMultiStatus || resp.Data.Succeeded != 2 || resp.Data.Items[1].Status != http.StatusBadRequest {
+		t.Fatalf("best-effort post batch = %d %+v", status, resp.Data)
+	}
+
+	// Batch delete needs the author
+	status, created := call[[]postDto.PostResponse](t, router, http.MethodGet, "/api/v1/posts", nil)
+	if status != http.StatusOK || len(created.Data) != 2 {
+		t.Fatalf("posts after best-effort batch = %d %+v", status, created.Data)
+	}
+	deletes := postDto.BatchDeletePostsRequest{Items: []uint{created.Data[0].ID, created.Data[1].ID}}
+	status, resp = call[batch.Result](t, router, http.MethodPost, "/api/v1/posts/batch-delete", deletes,
+		login(t, router, alice.Username)...)
+	if status != http.StatusOK || resp.Data.Succeeded != 2 {
+		t.Fatalf("post batch delete = %d %+v", status, resp.Data)
+	}
+	status, remaining := call[[]postDto.PostResponse](t, router, http.MethodGet, "/api/v1/posts", nil)
+	if status != http.StatusOK || len(remaining.Data) != 0 {
+		t.Fatalf("posts after batch delete = %d %+v", status, remaining.Data)
+	}
+}
+
+func TestUserChangesNeedTheirOwnToken(t *testing.T) {
+	router := newTestServer(t)
+	alice := createUser(t, router, "alice")
+	bob := createUser(t, router, "bob")
+	alicePath := fmt.Sprintf("/api/v1/users/%d", alice.ID)
+	update := userDto.UpdateUserRequest{Username: "alice", Email: "alice@example.org", Name: "Alice"}
+
+	status, resp := call[userDto.LoginResponse](t, router, http.MethodPost, "/api/v1/users/login", userDto.LoginRequest{
+		Email: "alice@example.com", Password: "wrong-password",
+	})
+	if status != http.StatusUnauthorized {
+		t.Fatalf("login with a wrong password = %d %+v, want 401", status, resp)
+	}
+
+	tests := []struct {
+		name    string
+		headers []string
+		status  int
+	}{
+		{"anonymous", nil, http.StatusUnauthorized},
+		{"claimed user ID", []string{"X-User-ID", fmt.Sprint(alice.ID)}, http.StatusUnauthorized},
+		{"forged token", []string{"Authorization", "Bearer forged"}, http.StatusUnauthorized},
+		{"other user", login(t, router, "bob"), http.StatusForbidden},
+		{"same user", login(t, router, "alice"), http.StatusOK},
+	}
+	for _, tt := range tests {
+		status, resp := call[userDto.UserResponse](t, router, http.MethodPut, alicePath, update, tt.headers...)
+		if status != tt.status {
+			t.Errorf("%s: PUT %s = %d %s, want %d", tt.name, alicePath, status, resp.Error, tt.status)
+		}
+	}
+
+	// Batch items addressing other accounts fail on their own
+	deletes := userDto.BatchDeleteUsersRequest{Items: []uint{alice.ID, bob.ID}}
+	deletes.Mode = batch.BestEffort
+	status, result := call[batch.Result](t, router, http.MethodPost, "/api/v1/users/batch-delete", deletes,
+		login(t, router, "bob")...)
+	if status != http.StatusMultiStatus || result.Data.Items[0].Status != http.StatusForbidden ||
+		result.Data.Items[1].Status != http.StatusOK {
+		t.Fatalf("batch delete of another account = %d %+v", status, result.Data)
+	}
+}
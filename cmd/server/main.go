@@ -1,59 +1,125 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"study-go-controller/pkg/config"
 	"study-go-controller/pkg/container"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
 
 func main() {
+	// CLI subcommands, e.g. `server openapi -o openapi.json` or `server routes`
+	if handled, err := runCommand(os.Args[1:]); handled {
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found")
 	}
 
 	// Initialize DI container with automatic route registration
-	c, err := container.NewContainer()
+	cfg := config.Load()
+	c, err := container.NewContainer(container.WithConfig(cfg))
 	if err != nil {
 		log.Fatal("Failed to initialize container:", err)
 	}
 
 	// Initialize Gin router
 	router := gin.Default()
+	// Client IPs, e.g. for rate limits, only come from X-Forwarded-For when a trusted proxy sent it
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
 
 	// 🚀 Register all routes automatically
 	c.RegisterRoutes(router)
 
-	// Health check endpoint
-	router.GET("/health", func(ctx *gin.Context) {
-		registeredRoutes := c.GetRegisteredRoutes()
-		ctx.JSON(200, gin.H{
-			"status":       "ok",
-			"message":      "Server is running with automatic routing",
-			"total_routes": len(registeredRoutes),
-			"auto_routes":  registeredRoutes,
-		})
-	})
-
-	// Get port from environment or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	port := cfg.Port
 
 	// Print registered routes for debugging
 	routes := c.GetRegisteredRoutes()
 	log.Println("\n🚀 ===== AUTOMATIC ROUTE REGISTRATION SUMMARY =====")
 	for _, route := range routes {
-		log.Printf("   %s %s", route.Method, route.Path)
+		log.Printf("   [%s] %s %s", route.Version, route.Method, route.Path)
 	}
 	log.Printf("📡 Total: %d routes automatically registered\n", len(routes))
 
+	// Start components in dependency order; the context is cancelled on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := c.Start(ctx); err != nil {
+		// Start already stopped what it had started
+		log.Fatal("Failed to start components:", err)
+	}
+
+	servers := []*http.Server{{Addr: ":" + port, Handler: router}}
+	// Internal endpoints get their own listener, kept off the public port
+	if cfg.AdminAddr != "" {
+		admin := gin.New()
+		admin.Use(gin.Recovery())
+		c.RegisterAdminRoutes(admin)
+		servers = append(servers, &http.Server{Addr: cfg.AdminAddr, Handler: admin})
+	}
+
+	serverErr := make(chan error, len(servers))
+	for _, s := range servers {
+		go func(s *http.Server) {
+			if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}(s)
+	}
 	log.Printf("🌟 Server starting on port %s with automatic routing enabled!", port)
-	if err := router.Run(":" + port); err != nil {
+	if cfg.AdminAddr != "" {
+		log.Printf("🔒 Internal endpoints listening on %s", cfg.AdminAddr)
+	}
+
+	select {
+	case err := <-serverErr:
+		shutdownContainer(c, cfg.ShutdownTimeout)
 		log.Fatal("Failed to start server:", err)
+	case <-ctx.Done():
+		stop()
+		log.Println("🛑 Shutdown signal received, draining connections...")
+	}
+
+	// Drain in-flight requests, then stop components in reverse order
+	timeout := cfg.ShutdownTimeout
+	drainCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	started := time.Now()
+	drained := true
+	for _, s := range servers {
+		if err := s.Shutdown(drainCtx); err != nil {
+			log.Printf("⚠️ HTTP server %s did not drain within %s: %v", s.Addr, timeout, err)
+			drained = false
+		}
+	}
+	if drained {
+		log.Printf("✅ HTTP server drained (%s)", time.Since(started))
+	}
+	shutdownContainer(c, timeout)
+	log.Println("👋 Server stopped")
+}
+
+// shutdownContainer stops the container's components within timeout
+func shutdownContainer(c *container.Container, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := c.Stop(ctx); err != nil {
+		log.Printf("⚠️ Some components failed to stop: %v", err)
 	}
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	postDto "study-go-controller/internal/domain/post/dto"
+	postEntity "study-go-controller/internal/domain/post/entity"
+	userEntity "study-go-controller/internal/domain/user/entity"
+	"study-go-controller/pkg/config"
+	"study-go-controller/pkg/container"
+	"study-go-controller/pkg/database"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Databases created by the startup auto-migration, before versioned
+// migrations, keep their tables and serve the API once migrated
+func TestMigrationsAdoptAutoMigratedTables(t *testing.T) {
+	db, err := database.NewDatabase(config.DatabaseConfig{Driver: database.DriverSQLite, Name: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	quiet := db.DB.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+	if err := quiet.AutoMigrate(&userEntity.User{}, &postEntity.Post{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := quiet.Create(&userEntity.User{Username: "legacy", Email: "legacy@example.com", Password: "x", Name: "Legacy"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := container.NewContainer(
+		container.WithConfig(&config.Config{MigrateOnStart: container.MigrateUp}),
+		container.WithDB(quiet),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Stop(context.Background()) })
+	router := gin.New()
+	c.RegisterRoutes(router)
+
+	status, resp := call[postDto.PostResponse](t, router, http.MethodPost, "/api/v1/posts", postDto.CreatePostRequest{
+		Title: "Still here", Content: "The legacy user's tables were kept", AuthorID: 1,
+	})
+	if status != http.StatusCreated || resp.Data.AuthorID != 1 {
+		t.Fatalf("POST /api/v1/posts on an adopted database = %d %s", status, resp.Error)
+	}
+}
@@ -0,0 +1,9 @@
+package main
+
+// Domain modules register themselves with the container when imported;
+// drop an import to remove a domain from the build, or switch one off at
+// runtime with MODULES_DISABLED.
+import (
+	_ "study-go-controller/internal/domain/post"
+	_ "study-go-controller/internal/domain/user"
+)
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"study-go-controller/internal/domain/user/dto"
+	"study-go-controller/internal/domain/user/entity"
+	"study-go-controller/internal/domain/user/handler"
+	"study-go-controller/internal/domain/user/repository"
+	"study-go-controller/internal/domain/user/service"
+	"study-go-controller/pkg/config"
+	"study-go-controller/pkg/container"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// fakeUserRepository keeps users in memory; the embedded interface panics
+// for the methods the test doesn't need
+type fakeUserRepository struct {
+	repository.UserRepository
+
+	mu    sync.Mutex
+	users []*entity.User
+}
+
+func (r *fakeUserRepository) Create(user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user.ID = uint(len(r.users) + 1)
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *fakeUserRepository) GetByID(id uint) (*entity.User, error) {
+	return r.find(func(user *entity.User) bool { return user.ID == id })
+}
+
+func (r *fakeUserRepository) GetByEmail(email string) (*entity.User, error) {
+	return r.find(func(user *entity.User) bool { return user.Email == email })
+}
+
+func (r *fakeUserRepository) GetByUsername(username string) (*entity.User, error) {
+	return r.find(func(user *entity.User) bool { return user.Username == username })
+}
+
+func (r *fakeUserRepository) find(match func(*entity.User) bool) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if match(user) {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestOverrideReplacesScopedRepositoryPerRequest(t *testing.T) {
+	fake := &fakeUserRepository{users: []*entity.User{{ID: 1, Username: "fake", Email: "fake@example.com", Name: "Fake"}}}
+	// Without migrations the database has no users table, so only the fake can answer
+	router := newTestServer(t,
+		container.WithoutMigrations(),
+		container.WithOverride[repository.UserRepository](fake),
+	)
+
+	// Read requests get a scope bound to the database
+	status, resp := call[dto.UserResponse](t, router, http.MethodGet, "/api/v1/users/1", nil)
+	if status != http.StatusOK || resp.Data.Username != "fake" {
+		t.Fatalf("GET /api/v1/users/1 = %d %+v, want the fake's user", status, resp)
+	}
+
+	// Write requests get a scope bound to a transaction
+	created := createUser(t, router, "alice")
+	if created.ID != 2 || len(fake.users) != 2 || fake.users[1].Username != "alice" {
+		t.Fatalf("POST /api/v1/users created %+v; fake has %d users", created, len(fake.users))
+	}
+	status, resp = call[dto.UserResponse](t, router, http.MethodGet, "/api/v1/users/2", nil)
+	if status != http.StatusOK || resp.Data.Username != "alice" {
+		t.Fatalf("GET /api/v1/users/2 = %d %+v", status, resp)
+	}
+}
+
+func TestOnlyRepositoriesAreRequestScoped(t *testing.T) {
+	c, err := container.NewRouteContainer(container.WithConfig(&config.Config{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[reflect.Type]container.Lifetime{
+		reflect.TypeOf((*repository.UserRepository)(nil)).Elem(): container.Scoped,
+		reflect.TypeOf((*service.UserService)(nil)).Elem():       container.Singleton,
+		reflect.TypeOf(&handler.UserHandler{}):                   container.Singleton,
+	}
+	for typ, want := range tests {
+		if got, ok := c.Factory.Lifetime(typ); !ok || got != want {
+			t.Errorf("Lifetime(%s) = %s, want %s", typ, got, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"study-go-controller/pkg/config"
+	"study-go-controller/pkg/container"
+	"testing"
+)
+
+var (
+	bindingsFunc      = regexp.MustCompile(`^func \(\*(\w+)\) RouteBindings\(\)`)
+	conventionComment = regexp.MustCompile(`^\s*"(\w+)":.*// (\w+) (\S+) by convention$`)
+)
+
+// The routes routegen comments in zz_routes.go must be the ones the router
+// serves, i.e. the go:generate directives match the modules' mounts
+func TestGeneratedRouteCommentsMatchTheRouter(t *testing.T) {
+	c, err := container.NewRouteContainer(container.WithConfig(&config.Config{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(map[string]container.RouteInfo)
+	for _, route := range c.GetRegisteredRoutes() {
+		served[route.HandlerName+"."+route.MethodName] = route
+	}
+
+	files, err := filepath.Glob("../../internal/domain/*/handler/zz_routes.go")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no generated route files: %v", err)
+	}
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var handler string
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if match := bindingsFunc.FindStringSubmatch(scanner.Text()); match != nil {
+				handler = match[1]
+				continue
+			}
+			match := conventionComment.FindStringSubmatch(scanner.Text())
+			if match == nil {
+				continue
+			}
+			route, ok := served[handler+"."+match[1]]
+			if !ok {
+				t.Errorf("%s: %s.%s is commented as %s %s but not served", file, handler, match[1], match[2], match[3])
+				continue
+			}
+			if route.Method != match[2] || route.Path != match[3] {
+				t.Errorf("%s: %s.%s is commented as %s %s but served at %s %s",
+					file, handler, match[1], match[2], match[3], route.Method, route.Path)
+			}
+		}
+		f.Close()
+	}
+}
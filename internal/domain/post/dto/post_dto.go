@@ -3,6 +3,7 @@ package dto
 import (
 	"study-go-controller/internal/domain/post/entity"
 	userDto "study-go-controller/internal/domain/user/dto"
+	"study-go-controller/pkg/batch"
 	"time"
 )
 
@@ -19,6 +20,45 @@ type UpdatePostRequest struct {
 	Content string `json:"content" binding:"max=10000"`
 }
 
+// BatchCreatePostsRequest represents the request body for creating posts in bulk
+type BatchCreatePostsRequest struct {
+	batch.Options
+	Items []CreatePostRequest `json:"items" binding:"required,min=1,max=1000"`
+}
+
+// BatchUpdatePostItem identifies a post and its new values in a batch update
+type BatchUpdatePostItem struct {
+	ID uint `json:"id" binding:"required"`
+	UpdatePostRequest
+}
+
+// BatchUpdatePostsRequest represents the request body for updating posts in bulk
+type BatchUpdatePostsRequest struct {
+	batch.Options
+	Items []BatchUpdatePostItem `json:"items" binding:"required,min=1,max=1000"`
+}
+
+// BatchDeletePostsRequest represents the request body for deleting posts by ID in bulk
+type BatchDeletePostsRequest struct {
+	batch.Options
+	Items []uint `json:"items" binding:"required,min=1,max=1000"`
+}
+
+// GetPostRequest represents the path parameters for fetching a post
+type GetPostRequest struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// PostsByAuthorQuery represents the query parameters for listing an author's posts
+type PostsByAuthorQuery struct {
+	AuthorID uint `form:"author_id" binding:"required"`
+}
+
+// ListPostsQuery represents the optional filters for listing posts
+type ListPostsQuery struct {
+	AuthorID uint `form:"author_id"`
+}
+
 // PostResponse represents the response body for post data
 type PostResponse struct {
 	ID        uint                  `json:"id"`
@@ -59,6 +99,14 @@ func ToPostResponse(post *entity.Post) *PostResponse {
 	return response
 }
 
+// ToUpdatePostRequest captures a post's current values as the base for a patch
+func ToUpdatePostRequest(post *entity.Post) UpdatePostRequest {
+	return UpdatePostRequest{
+		Title:   post.Title,
+		Content: post.Content,
+	}
+}
+
 // ToPostListResponse converts Post entity to PostListResponse DTO (without content)
 func ToPostListResponse(post *entity.Post) *PostListResponse {
 	response := &PostListResponse{
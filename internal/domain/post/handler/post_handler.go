@@ -1,11 +1,18 @@
 package handler
 
+//go:generate go run study-go-controller/cmd/routegen -base /posts
+
 import (
+	"context"
 	"net/http"
-	"strconv"
 	"study-go-controller/internal/domain/post/dto"
 	"study-go-controller/internal/domain/post/service"
+	"study-go-controller/pkg/batch"
+	"study-go-controller/pkg/middleware"
+	"study-go-controller/pkg/patch"
 	"study-go-controller/pkg/response"
+	"study-go-controller/pkg/routing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,49 +30,71 @@ func NewPostHandler(postService service.PostService) *PostHandler {
 	}
 }
 
-// CreatePost handles POST /posts
-// 🔗 Auto Route: POST /api/v1/posts
-func (h *PostHandler) CreatePost(c *gin.Context) {
-	var req dto.CreatePostRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.ValidationErrorResponse(c, err)
-		return
+// Routes declares routes that don't follow the naming convention
+// and documents request/response types for the OpenAPI document
+func (h *PostHandler) Routes() []routing.RouteSpec {
+	return []routing.RouteSpec{
+		routing.Action("CreatePost").WithMessage("Post created successfully"),
+		routing.Action("GetPost").WithMessage("Post retrieved successfully"),
+		routing.Action("GetAllPosts").Returns([]dto.PostListResponse{}),
+		routing.Action("UpdatePost").
+			Accepts(dto.UpdatePostRequest{}).
+			Returns(dto.PostResponse{}).
+			Use(middleware.RequireUser()),
+		routing.Action("PatchPost").
+			Accepts(dto.UpdatePostRequest{}).
+			Returns(dto.PostResponse{}).
+			Use(middleware.RequireUser()),
+		routing.Action("DeletePost").Use(middleware.RequireUser()),
+		routing.Action("BatchCreatePosts").
+			Accepts(dto.BatchCreatePostsRequest{}).
+			Returns(batch.Result{}).
+			WithStatus(http.StatusCreated),
+		routing.Action("BatchUpdatePosts").
+			Accepts(dto.BatchUpdatePostsRequest{}).
+			Returns(batch.Result{}).
+			Use(middleware.RequireUser()),
+		routing.Action("BatchDeletePosts").
+			Accepts(dto.BatchDeletePostsRequest{}).
+			Returns(batch.Result{}).
+			Use(middleware.RequireUser()),
+		routing.GET("/by-author", "GetPostsByAuthor").
+			Accepts(dto.PostsByAuthorQuery{}).
+			Returns([]dto.PostListResponse{}).
+			Deprecate(routing.Deprecation{
+				Since:     time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC),
+				Sunset:    time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Successor: "/api/v2/posts",
+			}),
 	}
+}
 
-	post, err := h.postService.CreatePost(req.Title, req.Content, req.AuthorID)
+// CreatePost handles POST /posts
+// 🔗 Auto Route: POST /api/v1/posts
+func (h *PostHandler) CreatePost(ctx context.Context, req *dto.CreatePostRequest) (*dto.PostResponse, error) {
+	post, err := h.postService.WithContext(ctx).CreatePost(req.Title, req.Content, req.AuthorID)
 	if err != nil {
-		response.ErrorResponse(c, http.StatusBadRequest, err.Error())
-		return
+		return nil, response.NewError(http.StatusBadRequest, err.Error())
 	}
 
-	postResponse := dto.ToPostResponse(post)
-	response.SuccessResponse(c, http.StatusCreated, "Post created successfully", postResponse)
+	return dto.ToPostResponse(post), nil
 }
 
 // GetPost handles GET /posts/:id
 // 🔗 Auto Route: GET /api/v1/posts/:id
-func (h *PostHandler) GetPost(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
+func (h *PostHandler) GetPost(ctx context.Context, req *dto.GetPostRequest) (*dto.PostResponse, error) {
+	post, err := h.postService.WithContext(ctx).GetPostByID(req.ID)
 	if err != nil {
-		response.ErrorResponse(c, http.StatusBadRequest, "Invalid post ID")
-		return
-	}
-
-	post, err := h.postService.GetPostByID(uint(id))
-	if err != nil {
-		response.ErrorResponse(c, http.StatusNotFound, "Post not found")
-		return
+		return nil, response.WrapError(http.StatusNotFound, "Post not found", err)
 	}
 
-	postResponse := dto.ToPostResponse(post)
-	response.SuccessResponse(c, http.StatusOK, "Post retrieved successfully", postResponse)
+	return dto.ToPostResponse(post), nil
 }
 
 // GetAllPosts handles GET /posts
 // 🔗 Auto Route: GET /api/v1/posts
 func (h *PostHandler) GetAllPosts(c *gin.Context) {
-	posts, err := h.postService.GetAllPosts()
+	posts, err := h.postService.WithContext(c).GetAllPosts()
 	if err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -76,14 +105,9 @@ func (h *PostHandler) GetAllPosts(c *gin.Context) {
 }
 
 // UpdatePost handles PUT /posts/:id
-// 🔗 Auto Route: PUT /api/v1/posts/:id
+// 🔗 Auto Route: PUT /api/v1/posts/:id (requires a bearer token)
 func (h *PostHandler) UpdatePost(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
-	if err != nil {
-		response.ErrorResponse(c, http.StatusBadRequest, "Invalid post ID")
-		return
-	}
+	id := routing.UintParam(c, "id")
 
 	var req dto.UpdatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -91,10 +115,10 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	// TODO: Get author ID from authenticated user context
-	authorID := uint(1) // Placeholder - should come from auth middleware
+	// Set by middleware.RequireUser
+	authorID, _ := middleware.CurrentUserID(c)
 
-	post, err := h.postService.UpdatePost(uint(id), req.Title, req.Content, authorID)
+	post, err := h.postService.WithContext(c).UpdatePost(id, req.Title, req.Content, authorID)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -104,20 +128,48 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	response.SuccessResponse(c, http.StatusOK, "Post updated successfully", postResponse)
 }
 
-// DeletePost handles DELETE /posts/:id
-// 🔗 Auto Route: DELETE /api/v1/posts/:id
-func (h *PostHandler) DeletePost(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
+// PatchPost handles PATCH /posts/:id with a merge patch or JSON Patch body
+// 🔗 Auto Route: PATCH /api/v1/posts/:id (requires a bearer token)
+func (h *PostHandler) PatchPost(c *gin.Context) {
+	id := routing.UintParam(c, "id")
+	svc := h.postService.WithContext(c)
+
+	post, err := svc.GetPostByID(id)
 	if err != nil {
-		response.ErrorResponse(c, http.StatusBadRequest, "Invalid post ID")
+		response.ErrorResponse(c, http.StatusNotFound, "Post not found")
 		return
 	}
 
-	// TODO: Get author ID from authenticated user context
-	authorID := uint(1) // Placeholder - should come from auth middleware
+	// Apply the patch to the current state, then write only what changed
+	current := dto.ToUpdatePostRequest(post)
+	patched := current
+	if err := patch.Bind(c, &patched); err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	// Set by middleware.RequireUser
+	authorID, _ := middleware.CurrentUserID(c)
+
+	post, err = svc.PatchPost(id, patch.Changes(current, patched), authorID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	if err := h.postService.DeletePost(uint(id), authorID); err != nil {
+	postResponse := dto.ToPostResponse(post)
+	response.SuccessResponse(c, http.StatusOK, "Post updated successfully", postResponse)
+}
+
+// DeletePost handles DELETE /posts/:id
+// 🔗 Auto Route: DELETE /api/v1/posts/:id (requires a bearer token)
+func (h *PostHandler) DeletePost(c *gin.Context) {
+	id := routing.UintParam(c, "id")
+
+	// Set by middleware.RequireUser
+	authorID, _ := middleware.CurrentUserID(c)
+
+	if err := h.postService.WithContext(c).DeletePost(id, authorID); err != nil {
 		response.ErrorResponse(c, http.StatusForbidden, err.Error())
 		return
 	}
@@ -125,23 +177,86 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	response.SuccessResponse(c, http.StatusOK, "Post deleted successfully", nil)
 }
 
-// 🆕 GetPostsByAuthor handles GET /posts with author filter
-// Note: This will be auto-registered as GET /posts/:id due to naming convention
-// For custom routes, we might need a different approach
-func (h *PostHandler) GetPostsByAuthor(c *gin.Context) {
-	authorIDParam := c.Query("author_id")
-	if authorIDParam == "" {
-		response.ErrorResponse(c, http.StatusBadRequest, "Author ID is required")
+// BatchCreatePosts handles POST /posts/batch-create
+// 🔗 Auto Route: POST /api/v1/posts/batch-create
+func (h *PostHandler) BatchCreatePosts(c *gin.Context) {
+	var req dto.BatchCreatePostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err)
 		return
 	}
 
-	authorID, err := strconv.ParseUint(authorIDParam, 10, 32)
-	if err != nil {
-		response.ErrorResponse(c, http.StatusBadRequest, "Invalid author ID")
+	svc := h.postService.WithContext(c)
+	result := batch.Run(req.Mode, req.Items, svc, svc.Transaction,
+		func(svc service.PostService, item dto.CreatePostRequest) (interface{}, error) {
+			post, err := svc.CreatePost(item.Title, item.Content, item.AuthorID)
+			if err != nil {
+				return nil, err
+			}
+			return dto.ToPostResponse(post), nil
+		})
+
+	batch.Respond(c, http.StatusCreated, "Posts created successfully", result)
+}
+
+// BatchUpdatePosts handles POST /posts/batch-update
+// 🔗 Auto Route: POST /api/v1/posts/batch-update (requires a bearer token)
+func (h *PostHandler) BatchUpdatePosts(c *gin.Context) {
+	var req dto.BatchUpdatePostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Set by middleware.RequireUser
+	authorID, _ := middleware.CurrentUserID(c)
+
+	svc := h.postService.WithContext(c)
+	result := batch.Run(req.Mode, req.Items, svc, svc.Transaction,
+		func(svc service.PostService, item dto.BatchUpdatePostItem) (interface{}, error) {
+			post, err := svc.UpdatePost(item.ID, item.Title, item.Content, authorID)
+			if err != nil {
+				return nil, err
+			}
+			return dto.ToPostResponse(post), nil
+		})
+
+	batch.Respond(c, http.StatusOK, "Posts updated successfully", result)
+}
+
+// BatchDeletePosts handles POST /posts/batch-delete
+// 🔗 Auto Route: POST /api/v1/posts/batch-delete (requires a bearer token)
+func (h *PostHandler) BatchDeletePosts(c *gin.Context) {
+	var req dto.BatchDeletePostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Set by middleware.RequireUser
+	authorID, _ := middleware.CurrentUserID(c)
+
+	svc := h.postService.WithContext(c)
+	result := batch.Run(req.Mode, req.Items, svc, svc.Transaction,
+		func(svc service.PostService, id uint) (interface{}, error) {
+			return nil, svc.DeletePost(id, authorID)
+		})
+
+	batch.Respond(c, http.StatusOK, "Posts deleted successfully", result)
+}
+
+// 🆕 GetPostsByAuthor handles GET /posts/by-author?author_id=
+// 🔗 Declared Route: GET /api/v1/posts/by-author
+//
+// Deprecated: replaced by GET /api/v2/posts?author_id=
+func (h *PostHandler) GetPostsByAuthor(c *gin.Context) {
+	var query dto.PostsByAuthorQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ValidationErrorResponse(c, err)
 		return
 	}
 
-	posts, err := h.postService.GetPostsByAuthorID(uint(authorID))
+	posts, err := h.postService.WithContext(c).GetPostsByAuthorID(query.AuthorID)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
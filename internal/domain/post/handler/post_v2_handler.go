@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"study-go-controller/internal/domain/post/dto"
+	"study-go-controller/internal/domain/post/entity"
+	"study-go-controller/internal/domain/post/service"
+	"study-go-controller/pkg/routing"
+)
+
+// PostV2Handler serves the post API changes introduced in /api/v2.
+// Every v1 route it doesn't override or remove is inherited unchanged.
+type PostV2Handler struct {
+	postService service.PostService
+}
+
+// NewPostV2Handler creates a new instance of PostV2Handler
+func NewPostV2Handler(postService service.PostService) *PostV2Handler {
+	return &PostV2Handler{
+		postService: postService,
+	}
+}
+
+// Routes folds the by-author listing into GET /posts
+func (h *PostV2Handler) Routes() []routing.RouteSpec {
+	return []routing.RouteSpec{
+		routing.Action("GetAllPosts").WithMessage("Posts retrieved successfully"),
+		routing.Remove(http.MethodGet, "/by-author"),
+	}
+}
+
+// GetAllPosts handles GET /posts with an optional author filter
+// 🔗 Auto Route: GET /api/v2/posts?author_id=
+func (h *PostV2Handler) GetAllPosts(ctx context.Context, req *dto.ListPostsQuery) ([]*dto.PostListResponse, error) {
+	svc := h.postService.WithContext(ctx)
+	var posts []*entity.Post
+	var err error
+	if req.AuthorID != 0 {
+		posts, err = svc.GetPostsByAuthorID(req.AuthorID)
+	} else {
+		posts, err = svc.GetAllPosts()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToPostListResponseList(posts), nil
+}
@@ -0,0 +1,52 @@
+// Code generated by routegen. DO NOT EDIT.
+
+package handler
+
+import (
+	"context"
+	"study-go-controller/internal/domain/post/dto"
+	"study-go-controller/pkg/routing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteBindings implements routing.BindingProvider
+func (*PostHandler) RouteBindings() map[string]routing.Binding {
+	return map[string]routing.Binding{
+		"BatchCreatePosts": {Gin: func(h interface{}, c *gin.Context) { h.(*PostHandler).BatchCreatePosts(c) }}, // POST /posts/batch-create by convention
+		"BatchDeletePosts": {Gin: func(h interface{}, c *gin.Context) { h.(*PostHandler).BatchDeletePosts(c) }}, // POST /posts/batch-delete by convention
+		"BatchUpdatePosts": {Gin: func(h interface{}, c *gin.Context) { h.(*PostHandler).BatchUpdatePosts(c) }}, // POST /posts/batch-update by convention
+		"CreatePost": { // POST /posts by convention
+			Typed: func(h interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				resp, err := h.(*PostHandler).CreatePost(ctx, req.(*dto.CreatePostRequest))
+				return resp, err
+			},
+			NewRequest: func() interface{} { return new(dto.CreatePostRequest) },
+		},
+		"DeletePost":  {Gin: func(h interface{}, c *gin.Context) { h.(*PostHandler).DeletePost(c) }},  // DELETE /posts/:id by convention
+		"GetAllPosts": {Gin: func(h interface{}, c *gin.Context) { h.(*PostHandler).GetAllPosts(c) }}, // GET /posts by convention
+		"GetPost": { // GET /posts/:id by convention
+			Typed: func(h interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				resp, err := h.(*PostHandler).GetPost(ctx, req.(*dto.GetPostRequest))
+				return resp, err
+			},
+			NewRequest: func() interface{} { return new(dto.GetPostRequest) },
+		},
+		"GetPostsByAuthor": {Gin: func(h interface{}, c *gin.Context) { h.(*PostHandler).GetPostsByAuthor(c) }}, // declared in Routes()
+		"PatchPost":        {Gin: func(h interface{}, c *gin.Context) { h.(*PostHandler).PatchPost(c) }},        // PATCH /posts/:id by convention
+		"UpdatePost":       {Gin: func(h interface{}, c *gin.Context) { h.(*PostHandler).UpdatePost(c) }},       // PUT /posts/:id by convention
+	}
+}
+
+// RouteBindings implements routing.BindingProvider
+func (*PostV2Handler) RouteBindings() map[string]routing.Binding {
+	return map[string]routing.Binding{
+		"GetAllPosts": { // GET /posts by convention
+			Typed: func(h interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				resp, err := h.(*PostV2Handler).GetAllPosts(ctx, req.(*dto.ListPostsQuery))
+				return resp, err
+			},
+			NewRequest: func() interface{} { return new(dto.ListPostsQuery) },
+		},
+	}
+}
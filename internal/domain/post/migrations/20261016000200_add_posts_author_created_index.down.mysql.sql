@@ -0,0 +1,2 @@
+-- MySQL drops indexes per table
+DROP INDEX idx_posts_author_created ON posts;
@@ -0,0 +1,2 @@
+-- Serves "posts by author, newest first"
+CREATE INDEX idx_posts_author_created ON posts (author_id, created_at);
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"embed"
+	"study-go-controller/pkg/migrate"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// files holds the SQL migrations, see migrate.FromFS
+//
+//go:embed *.sql
+var files embed.FS
+
+// All returns the post domain's migrations for dialect
+func All(dialect string) ([]migrate.Migration, error) {
+	sqlMigrations, err := migrate.FromFS(files, dialect)
+	if err != nil {
+		return nil, err
+	}
+	return append([]migrate.Migration{createPosts}, sqlMigrations...), nil
+}
+
+// user is the part of the users table posts reference
+type user struct {
+	ID uint `gorm:"primarykey"`
+}
+
+func (user) TableName() string { return "users" }
+
+// post is the posts table as of createPosts. Migrations keep their own copy
+// of the schema, so later changes to entity.Post don't rewrite history.
+type post struct {
+	ID        uint   `gorm:"primarykey"`
+	Title     string `gorm:"not null"`
+	Content   string `gorm:"type:text"`
+	AuthorID  uint   `gorm:"not null"`
+	Author    user   `gorm:"foreignKey:AuthorID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (post) TableName() string { return "posts" }
+
+// createPosts creates the table and its foreign key from the copy above.
+// Databases created by the former startup auto-migration already have it
+// and keep it as is.
+var createPosts = migrate.Go(20261016000100, "create_posts", func(tx *gorm.DB) error {
+	if tx.Migrator().HasTable(&post{}) {
+		return nil
+	}
+	return tx.Migrator().CreateTable(&post{})
+}, func(tx *gorm.DB) error {
+	return tx.Migrator().DropTable("posts")
+})
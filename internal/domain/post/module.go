@@ -0,0 +1,46 @@
+package post
+
+import (
+	"study-go-controller/internal/domain/post/handler"
+	"study-go-controller/internal/domain/post/migrations"
+	"study-go-controller/internal/domain/post/repository"
+	"study-go-controller/internal/domain/post/service"
+	"study-go-controller/pkg/container"
+	"study-go-controller/pkg/migrate"
+)
+
+func init() {
+	container.RegisterModule(Module{})
+}
+
+// Module wires the post domain into the container
+type Module struct{}
+
+// Name implements container.Module
+func (Module) Name() string { return "posts" }
+
+// BasePath implements container.Module
+func (Module) BasePath() string { return "/posts" }
+
+// Migrations implements container.Module
+func (Module) Migrations(dialect string) ([]migrate.Migration, error) {
+	return migrations.All(dialect)
+}
+
+// Providers implements container.Module
+func (Module) Providers() []interface{} {
+	return []interface{}{
+		repository.NewPostRepository,
+		service.NewPostService,
+		handler.NewPostHandler,
+		handler.NewPostV2Handler,
+	}
+}
+
+// Handlers implements container.Module; v2 inherits every v1 route it doesn't override
+func (Module) Handlers() []container.HandlerMount {
+	return []container.HandlerMount{
+		container.Mount((*handler.PostHandler)(nil)),
+		container.Mount((*handler.PostV2Handler)(nil), container.InVersion("v2")),
+	}
+}
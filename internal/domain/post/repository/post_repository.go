@@ -12,8 +12,10 @@ type PostRepository interface {
 	GetByID(id uint) (*entity.Post, error)
 	GetByAuthorID(authorID uint) ([]*entity.Post, error)
 	Update(post *entity.Post) error
+	UpdateFields(id uint, fields map[string]interface{}) error
 	Delete(id uint) error
 	GetAll() ([]*entity.Post, error)
+	Transaction(fn func(PostRepository) error) error
 }
 
 // postRepository implements PostRepository interface
@@ -55,6 +57,11 @@ func (r *postRepository) Update(post *entity.Post) error {
 	return r.db.Save(post).Error
 }
 
+// UpdateFields writes only the given columns of a post
+func (r *postRepository) UpdateFields(id uint, fields map[string]interface{}) error {
+	return r.db.Model(&entity.Post{}).Where("id = ?", id).Updates(fields).Error
+}
+
 // Delete soft deletes a post by ID
 func (r *postRepository) Delete(id uint) error {
 	return r.db.Delete(&entity.Post{}, id).Error
@@ -66,3 +73,11 @@ func (r *postRepository) GetAll() ([]*entity.Post, error) {
 	err := r.db.Preload("Author").Find(&posts).Error
 	return posts, err
 }
+
+// Transaction runs fn with a repository bound to a single database transaction,
+// committing when fn returns nil and rolling back otherwise
+func (r *postRepository) Transaction(fn func(PostRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&postRepository{db: tx})
+	})
+}
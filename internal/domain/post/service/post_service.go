@@ -1,9 +1,12 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"study-go-controller/internal/domain/post/entity"
 	"study-go-controller/internal/domain/post/repository"
+	"study-go-controller/pkg/container"
 )
 
 // PostService defines the contract for post business logic
@@ -12,8 +15,11 @@ type PostService interface {
 	GetPostByID(id uint) (*entity.Post, error)
 	GetPostsByAuthorID(authorID uint) ([]*entity.Post, error)
 	UpdatePost(id uint, title, content string, authorID uint) (*entity.Post, error)
+	PatchPost(id uint, changes map[string]interface{}, authorID uint) (*entity.Post, error)
 	DeletePost(id uint, authorID uint) error
 	GetAllPosts() ([]*entity.Post, error)
+	Transaction(fn func(PostService) error) error
+	WithContext(ctx context.Context) PostService
 }
 
 // postService implements PostService interface
@@ -84,6 +90,40 @@ func (s *postService) UpdatePost(id uint, title, content string, authorID uint)
 	return s.postRepo.GetByID(id)
 }
 
+// patchablePostFields lists the columns PatchPost may write
+var patchablePostFields = map[string]bool{"title": true, "content": true}
+
+// PatchPost writes only the changed fields of a post, keyed by column name
+func (s *postService) PatchPost(id uint, changes map[string]interface{}, authorID uint) (*entity.Post, error) {
+	post, err := s.postRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if the user is the author of the post
+	if post.AuthorID != authorID {
+		return nil, errors.New("unauthorized: only the author can update this post")
+	}
+
+	for field := range changes {
+		if !patchablePostFields[field] {
+			return nil, fmt.Errorf("field %q cannot be patched", field)
+		}
+	}
+	if title, ok := changes["title"]; ok && title == "" {
+		return nil, errors.New("title is required")
+	}
+	if len(changes) == 0 {
+		return post, nil
+	}
+
+	if err := s.postRepo.UpdateFields(id, changes); err != nil {
+		return nil, err
+	}
+
+	return s.postRepo.GetByID(id)
+}
+
 // DeletePost deletes a post by ID
 func (s *postService) DeletePost(id uint, authorID uint) error {
 	post, err := s.postRepo.GetByID(id)
@@ -103,3 +143,16 @@ func (s *postService) DeletePost(id uint, authorID uint) error {
 func (s *postService) GetAllPosts() ([]*entity.Post, error) {
 	return s.postRepo.GetAll()
 }
+
+// Transaction runs fn with a PostService whose writes share one database transaction
+func (s *postService) Transaction(fn func(PostService) error) error {
+	return s.postRepo.Transaction(func(repo repository.PostRepository) error {
+		return fn(NewPostService(repo))
+	})
+}
+
+// WithContext returns a PostService using the repository of ctx's request
+// scope, so its writes join the request's transaction
+func (s *postService) WithContext(ctx context.Context) PostService {
+	return NewPostService(container.FromScope(ctx, s.postRepo))
+}
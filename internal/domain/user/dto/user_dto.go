@@ -2,6 +2,7 @@ package dto
 
 import (
 	"study-go-controller/internal/domain/user/entity"
+	"study-go-controller/pkg/batch"
 	"time"
 )
 
@@ -20,6 +21,30 @@ type UpdateUserRequest struct {
 	Name     string `json:"name" binding:"required,min=2,max=100"`
 }
 
+// BatchCreateUsersRequest represents the request body for creating users in bulk
+type BatchCreateUsersRequest struct {
+	batch.Options
+	Items []CreateUserRequest `json:"items" binding:"required,min=1,max=1000"`
+}
+
+// BatchUpdateUserItem identifies a user and its new values in a batch update
+type BatchUpdateUserItem struct {
+	ID uint `json:"id" binding:"required"`
+	UpdateUserRequest
+}
+
+// BatchUpdateUsersRequest represents the request body for updating users in bulk
+type BatchUpdateUsersRequest struct {
+	batch.Options
+	Items []BatchUpdateUserItem `json:"items" binding:"required,min=1,max=1000"`
+}
+
+// BatchDeleteUsersRequest represents the request body for deleting users by ID in bulk
+type BatchDeleteUsersRequest struct {
+	batch.Options
+	Items []uint `json:"items" binding:"required,min=1,max=1000"`
+}
+
 // UserResponse represents the response body for user data
 type UserResponse struct {
 	ID        uint      `json:"id"`
@@ -36,6 +61,14 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// LoginResponse carries the bearer token for authenticated requests
+type LoginResponse struct {
+	Token     string       `json:"token"`
+	TokenType string       `json:"token_type"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	User      UserResponse `json:"user"`
+}
+
 // ChangePasswordRequest represents the request body for changing password
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
@@ -54,6 +87,15 @@ func ToUserResponse(user *entity.User) *UserResponse {
 	}
 }
 
+// ToUpdateUserRequest captures a user's current values as the base for a patch
+func ToUpdateUserRequest(user *entity.User) UpdateUserRequest {
+	return UpdateUserRequest{
+		Username: user.Username,
+		Email:    user.Email,
+		Name:     user.Name,
+	}
+}
+
 // ToUserResponseList converts slice of User entities to slice of UserResponse DTOs
 func ToUserResponseList(users []*entity.User) []*UserResponse {
 	responses := make([]*UserResponse, len(users))
@@ -1,11 +1,17 @@
 package handler
 
+//go:generate go run study-go-controller/cmd/routegen -base /users -singletons Profile
+
 import (
 	"net/http"
-	"strconv"
 	"study-go-controller/internal/domain/user/dto"
 	"study-go-controller/internal/domain/user/service"
+	"study-go-controller/pkg/auth"
+	"study-go-controller/pkg/batch"
+	"study-go-controller/pkg/middleware"
+	"study-go-controller/pkg/patch"
 	"study-go-controller/pkg/response"
+	"study-go-controller/pkg/routing"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,12 +19,52 @@ import (
 // UserHandler handles HTTP requests for user operations
 type UserHandler struct {
 	userService service.UserService
+	tokens      *auth.Tokens
 }
 
 // NewUserHandler creates a new instance of UserHandler
-func NewUserHandler(userService service.UserService) *UserHandler {
+func NewUserHandler(userService service.UserService, tokens *auth.Tokens) *UserHandler {
 	return &UserHandler{
 		userService: userService,
+		tokens:      tokens,
+	}
+}
+
+// errNotOwnAccount fails batch items addressing another user's account
+var errNotOwnAccount = response.NewError(http.StatusForbidden, "Users can only change their own account")
+
+// Routes declares routes that don't follow the naming convention
+// and documents request/response types for the OpenAPI document
+func (h *UserHandler) Routes() []routing.RouteSpec {
+	return []routing.RouteSpec{
+		routing.Action("CreateUser").Accepts(dto.CreateUserRequest{}).Returns(dto.UserResponse{}),
+		routing.Action("GetUser").Returns(dto.UserResponse{}),
+		routing.Action("GetAllUsers").Returns([]dto.UserResponse{}),
+		routing.Action("UpdateUser").
+			Accepts(dto.UpdateUserRequest{}).
+			Returns(dto.UserResponse{}).
+			Use(middleware.RequireSelf("id")),
+		routing.Action("PatchUser").
+			Accepts(dto.UpdateUserRequest{}).
+			Returns(dto.UserResponse{}).
+			Use(middleware.RequireSelf("id")),
+		routing.Action("DeleteUser").Use(middleware.RequireSelf("id")),
+		routing.PUT("/:id/password", "ChangePassword").
+			Accepts(dto.ChangePasswordRequest{}).
+			Use(middleware.RequireSelf("id")),
+		routing.POST("/login", "Login").Accepts(dto.LoginRequest{}).Returns(dto.LoginResponse{}),
+		routing.Action("BatchCreateUsers").
+			Accepts(dto.BatchCreateUsersRequest{}).
+			Returns(batch.Result{}).
+			WithStatus(http.StatusCreated),
+		routing.Action("BatchUpdateUsers").
+			Accepts(dto.BatchUpdateUsersRequest{}).
+			Returns(batch.Result{}).
+			Use(middleware.RequireUser()),
+		routing.Action("BatchDeleteUsers").
+			Accepts(dto.BatchDeleteUsersRequest{}).
+			Returns(batch.Result{}).
+			Use(middleware.RequireUser()),
 	}
 }
 
@@ -30,7 +76,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.CreateUser(req.Username, req.Email, req.Password, req.Name)
+	user, err := h.userService.WithContext(c).CreateUser(req.Username, req.Email, req.Password, req.Name)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -40,16 +86,41 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	response.SuccessResponse(c, http.StatusCreated, "User created successfully", userResponse)
 }
 
-// GetUser handles GET /users/:id
-func (h *UserHandler) GetUser(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
+// Login handles POST /users/login and returns a bearer token
+// for the routes that need an authenticated user
+func (h *UserHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err)
+		return
+	}
+
+	svc := h.userService.WithContext(c)
+	user, err := svc.GetUserByEmail(req.Email)
+	if err != nil || !svc.ValidatePassword(req.Password, user.Password) {
+		response.ErrorResponse(c, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+
+	token, expiresAt, err := h.tokens.Issue(user.ID)
 	if err != nil {
-		response.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to issue token")
 		return
 	}
 
-	user, err := h.userService.GetUserByID(uint(id))
+	response.SuccessResponse(c, http.StatusOK, "Logged in successfully", dto.LoginResponse{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresAt: expiresAt,
+		User:      *dto.ToUserResponse(user),
+	})
+}
+
+// GetUser handles GET /users/:id
+func (h *UserHandler) GetUser(c *gin.Context) {
+	id := routing.UintParam(c, "id")
+
+	user, err := h.userService.WithContext(c).GetUserByID(id)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusNotFound, "User not found")
 		return
@@ -61,7 +132,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 
 // GetAllUsers handles GET /users
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.userService.GetAllUsers()
+	users, err := h.userService.WithContext(c).GetAllUsers()
 	if err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -73,12 +144,7 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 
 // UpdateUser handles PUT /users/:id
 func (h *UserHandler) UpdateUser(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
-	if err != nil {
-		response.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
+	id := routing.UintParam(c, "id")
 
 	var req dto.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -86,7 +152,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.UpdateUser(uint(id), req.Username, req.Email, req.Name)
+	user, err := h.userService.WithContext(c).UpdateUser(id, req.Username, req.Email, req.Name)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -96,16 +162,40 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	response.SuccessResponse(c, http.StatusOK, "User updated successfully", userResponse)
 }
 
-// DeleteUser handles DELETE /users/:id
-func (h *UserHandler) DeleteUser(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
+// PatchUser handles PATCH /users/:id with a merge patch or JSON Patch body
+func (h *UserHandler) PatchUser(c *gin.Context) {
+	id := routing.UintParam(c, "id")
+	svc := h.userService.WithContext(c)
+
+	user, err := svc.GetUserByID(id)
 	if err != nil {
-		response.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		response.ErrorResponse(c, http.StatusNotFound, "User not found")
 		return
 	}
 
-	if err := h.userService.DeleteUser(uint(id)); err != nil {
+	// Apply the patch to the current state, then write only what changed
+	current := dto.ToUpdateUserRequest(user)
+	patched := current
+	if err := patch.Bind(c, &patched); err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	user, err = svc.PatchUser(id, patch.Changes(current, patched))
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userResponse := dto.ToUserResponse(user)
+	response.SuccessResponse(c, http.StatusOK, "User updated successfully", userResponse)
+}
+
+// DeleteUser handles DELETE /users/:id
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	id := routing.UintParam(c, "id")
+
+	if err := h.userService.WithContext(c).DeleteUser(id); err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -113,17 +203,87 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	response.SuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
 }
 
+// BatchCreateUsers handles POST /users/batch-create
+func (h *UserHandler) BatchCreateUsers(c *gin.Context) {
+	var req dto.BatchCreateUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err)
+		return
+	}
+
+	svc := h.userService.WithContext(c)
+	result := batch.Run(req.Mode, req.Items, svc, svc.Transaction,
+		func(svc service.UserService, item dto.CreateUserRequest) (interface{}, error) {
+			user, err := svc.CreateUser(item.Username, item.Email, item.Password, item.Name)
+			if err != nil {
+				return nil, err
+			}
+			return dto.ToUserResponse(user), nil
+		})
+
+	batch.Respond(c, http.StatusCreated, "Users created successfully", result)
+}
+
+// BatchUpdateUsers handles POST /users/batch-update
+func (h *UserHandler) BatchUpdateUsers(c *gin.Context) {
+	var req dto.BatchUpdateUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Set by middleware.RequireUser
+	callerID, _ := middleware.CurrentUserID(c)
+
+	svc := h.userService.WithContext(c)
+	result := batch.Run(req.Mode, req.Items, svc, svc.Transaction,
+		func(svc service.UserService, item dto.BatchUpdateUserItem) (interface{}, error) {
+			if item.ID != callerID {
+				return nil, errNotOwnAccount
+			}
+			user, err := svc.UpdateUser(item.ID, item.Username, item.Email, item.Name)
+			if err != nil {
+				return nil, err
+			}
+			return dto.ToUserResponse(user), nil
+		})
+
+	batch.Respond(c, http.StatusOK, "Users updated successfully", result)
+}
+
+// BatchDeleteUsers handles POST /users/batch-delete
+func (h *UserHandler) BatchDeleteUsers(c *gin.Context) {
+	var req dto.BatchDeleteUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Set by middleware.RequireUser
+	callerID, _ := middleware.CurrentUserID(c)
+
+	svc := h.userService.WithContext(c)
+	result := batch.Run(req.Mode, req.Items, svc, svc.Transaction,
+		func(svc service.UserService, id uint) (interface{}, error) {
+			if id != callerID {
+				return nil, errNotOwnAccount
+			}
+			// Report unknown IDs instead of silently deleting nothing
+			if _, err := svc.GetUserByID(id); err != nil {
+				return nil, err
+			}
+			return nil, svc.DeleteUser(id)
+		})
+
+	batch.Respond(c, http.StatusOK, "Users deleted successfully", result)
+}
+
 // 🆕 새로운 API 메서드 추가 예시
 // GetUserProfile handles GET /users/:id/profile
 func (h *UserHandler) GetUserProfile(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
-	if err != nil {
-		response.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
+	id := routing.UintParam(c, "id")
 
-	user, err := h.userService.GetUserByID(uint(id))
+	user, err := h.userService.WithContext(c).GetUserByID(id)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusNotFound, "User not found")
 		return
@@ -145,12 +305,7 @@ func (h *UserHandler) GetUserProfile(c *gin.Context) {
 
 // 🆕 ChangePassword handles PUT /users/:id/password
 func (h *UserHandler) ChangePassword(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.ParseUint(idParam, 10, 32)
-	if err != nil {
-		response.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
+	id := routing.UintParam(c, "id")
 
 	var req dto.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -159,14 +314,13 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	}
 
 	// 일단 임시로 사용자 존재 여부 확인
-	_, err = h.userService.GetUserByID(uint(id))
-	if err != nil {
+	if _, err := h.userService.WithContext(c).GetUserByID(id); err != nil {
 		response.ErrorResponse(c, http.StatusNotFound, "User not found")
 		return
 	}
 
 	// TODO: Service에 ChangePassword 메서드 추가 필요
-	// if err := h.userService.ChangePassword(uint(id), req.CurrentPassword, req.NewPassword); err != nil {
+	// if err := h.userService.ChangePassword(id, req.CurrentPassword, req.NewPassword); err != nil {
 	//     response.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	//     return
 	// }
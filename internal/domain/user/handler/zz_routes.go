@@ -0,0 +1,27 @@
+// Code generated by routegen. DO NOT EDIT.
+
+package handler
+
+import (
+	"study-go-controller/pkg/routing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteBindings implements routing.BindingProvider
+func (*UserHandler) RouteBindings() map[string]routing.Binding {
+	return map[string]routing.Binding{
+		"BatchCreateUsers": {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).BatchCreateUsers(c) }}, // POST /users/batch-create by convention
+		"BatchDeleteUsers": {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).BatchDeleteUsers(c) }}, // POST /users/batch-delete by convention
+		"BatchUpdateUsers": {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).BatchUpdateUsers(c) }}, // POST /users/batch-update by convention
+		"ChangePassword":   {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).ChangePassword(c) }},   // declared in Routes()
+		"CreateUser":       {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).CreateUser(c) }},       // POST /users by convention
+		"DeleteUser":       {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).DeleteUser(c) }},       // DELETE /users/:id by convention
+		"GetAllUsers":      {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).GetAllUsers(c) }},      // GET /users by convention
+		"GetUser":          {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).GetUser(c) }},          // GET /users/:id by convention
+		"GetUserProfile":   {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).GetUserProfile(c) }},   // GET /users/:id/profile by convention
+		"Login":            {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).Login(c) }},            // declared in Routes()
+		"PatchUser":        {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).PatchUser(c) }},        // PATCH /users/:id by convention
+		"UpdateUser":       {Gin: func(h interface{}, c *gin.Context) { h.(*UserHandler).UpdateUser(c) }},       // PUT /users/:id by convention
+	}
+}
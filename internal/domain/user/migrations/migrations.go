@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"study-go-controller/pkg/migrate"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// All returns the user domain's migrations
+func All(dialect string) ([]migrate.Migration, error) {
+	return []migrate.Migration{createUsers}, nil
+}
+
+// user is the users table as of createUsers. Migrations keep their own copy
+// of the schema, so later changes to entity.User don't rewrite history.
+type user struct {
+	ID        uint   `gorm:"primarykey"`
+	Username  string `gorm:"uniqueIndex;not null"`
+	Email     string `gorm:"uniqueIndex;not null"`
+	Password  string `gorm:"not null"`
+	Name      string `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (user) TableName() string { return "users" }
+
+// createUsers creates the table from the copy above. Databases created by
+// the former startup auto-migration already have it and keep it as is.
+var createUsers = migrate.Go(20261016000000, "create_users", func(tx *gorm.DB) error {
+	if tx.Migrator().HasTable(&user{}) {
+		return nil
+	}
+	return tx.Migrator().CreateTable(&user{})
+}, func(tx *gorm.DB) error {
+	return tx.Migrator().DropTable("users")
+})
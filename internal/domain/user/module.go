@@ -0,0 +1,54 @@
+package user
+
+import (
+	"study-go-controller/internal/domain/user/handler"
+	"study-go-controller/internal/domain/user/migrations"
+	"study-go-controller/internal/domain/user/repository"
+	"study-go-controller/internal/domain/user/service"
+	"study-go-controller/pkg/container"
+	"study-go-controller/pkg/middleware"
+	"study-go-controller/pkg/migrate"
+	"time"
+)
+
+func init() {
+	container.RegisterModule(Module{})
+}
+
+// Module wires the user domain into the container
+type Module struct{}
+
+// Name implements container.Module
+func (Module) Name() string { return "users" }
+
+// BasePath implements container.Module
+func (Module) BasePath() string { return "/users" }
+
+// Migrations implements container.Module
+func (Module) Migrations(dialect string) ([]migrate.Migration, error) {
+	return migrations.All(dialect)
+}
+
+// Providers implements container.Module
+func (Module) Providers() []interface{} {
+	return []interface{}{
+		repository.NewUserRepository,
+		service.NewUserService,
+		handler.NewUserHandler,
+	}
+}
+
+// Handlers implements container.Module
+func (Module) Handlers() []container.HandlerMount {
+	// Batch creation spends the same budget as single creation, a token per item
+	createLimit := middleware.NewRateLimiter(10, time.Minute)
+	return []container.HandlerMount{
+		container.Mount((*handler.UserHandler)(nil),
+			container.WithSingletons("Profile"),
+			container.WithMethodMiddleware("CreateUser", createLimit.Handler()),
+			container.WithMethodMiddleware("BatchCreateUsers", createLimit.Weighted(middleware.BatchItems)),
+			// Slows down password guessing
+			container.WithMethodMiddleware("Login", middleware.RateLimit(5, time.Minute)),
+		),
+	}
+}
@@ -13,8 +13,10 @@ type UserRepository interface {
 	GetByEmail(email string) (*entity.User, error)
 	GetByUsername(username string) (*entity.User, error)
 	Update(user *entity.User) error
+	UpdateFields(id uint, fields map[string]interface{}) error
 	Delete(id uint) error
 	GetAll() ([]*entity.User, error)
+	Transaction(fn func(UserRepository) error) error
 }
 
 // userRepository implements UserRepository interface
@@ -69,6 +71,11 @@ func (r *userRepository) Update(user *entity.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdateFields writes only the given columns of a user
+func (r *userRepository) UpdateFields(id uint, fields map[string]interface{}) error {
+	return r.db.Model(&entity.User{}).Where("id = ?", id).Updates(fields).Error
+}
+
 // Delete soft deletes a user by ID
 func (r *userRepository) Delete(id uint) error {
 	return r.db.Delete(&entity.User{}, id).Error
@@ -80,3 +87,11 @@ func (r *userRepository) GetAll() ([]*entity.User, error) {
 	err := r.db.Find(&users).Error
 	return users, err
 }
+
+// Transaction runs fn with a repository bound to a single database transaction,
+// committing when fn returns nil and rolling back otherwise
+func (r *userRepository) Transaction(fn func(UserRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&userRepository{db: tx})
+	})
+}
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"study-go-controller/internal/domain/user/entity"
 	"study-go-controller/internal/domain/user/repository"
+	"study-go-controller/pkg/container"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -15,9 +18,12 @@ type UserService interface {
 	GetUserByEmail(email string) (*entity.User, error)
 	GetUserByUsername(username string) (*entity.User, error)
 	UpdateUser(id uint, username, email, name string) (*entity.User, error)
+	PatchUser(id uint, changes map[string]interface{}) (*entity.User, error)
 	DeleteUser(id uint) error
 	GetAllUsers() ([]*entity.User, error)
 	ValidatePassword(password, hashedPassword string) bool
+	Transaction(fn func(UserService) error) error
+	WithContext(ctx context.Context) UserService
 }
 
 // userService implements UserService interface
@@ -106,6 +112,46 @@ func (s *userService) UpdateUser(id uint, username, email, name string) (*entity
 	return user, nil
 }
 
+// patchableUserFields lists the columns PatchUser may write
+var patchableUserFields = map[string]bool{"username": true, "email": true, "name": true}
+
+// PatchUser writes only the changed fields of a user, keyed by column name
+func (s *userService) PatchUser(id uint, changes map[string]interface{}) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for field := range changes {
+		if !patchableUserFields[field] {
+			return nil, fmt.Errorf("field %q cannot be patched", field)
+		}
+	}
+	if len(changes) == 0 {
+		return user, nil
+	}
+
+	// Check if email is already taken by another user
+	if email, ok := changes["email"].(string); ok {
+		if existingUser, _ := s.userRepo.GetByEmail(email); existingUser != nil && existingUser.ID != id {
+			return nil, errors.New("email is already taken")
+		}
+	}
+
+	// Check if username is already taken by another user
+	if username, ok := changes["username"].(string); ok {
+		if existingUser, _ := s.userRepo.GetByUsername(username); existingUser != nil && existingUser.ID != id {
+			return nil, errors.New("username is already taken")
+		}
+	}
+
+	if err := s.userRepo.UpdateFields(id, changes); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetByID(id)
+}
+
 // DeleteUser deletes a user by ID
 func (s *userService) DeleteUser(id uint) error {
 	return s.userRepo.Delete(id)
@@ -121,3 +167,16 @@ func (s *userService) ValidatePassword(password, hashedPassword string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
+
+// Transaction runs fn with a UserService whose writes share one database transaction
+func (s *userService) Transaction(fn func(UserService) error) error {
+	return s.userRepo.Transaction(func(repo repository.UserRepository) error {
+		return fn(NewUserService(repo))
+	})
+}
+
+// WithContext returns a UserService using the repository of ctx's request
+// scope, so its writes join the request's transaction
+func (s *userService) WithContext(ctx context.Context) UserService {
+	return NewUserService(container.FromScope(ctx, s.userRepo))
+}
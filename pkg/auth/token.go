@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MinSecretLength is the shortest signing key accepted, 256 bits for HS256
+const MinSecretLength = 32
+
+// ErrInvalidToken is returned for tokens that are malformed, expired or
+// signed with another key
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Tokens issues and verifies HS256-signed JWTs whose subject is a user ID
+type Tokens struct {
+	secret []byte
+	expiry time.Duration
+}
+
+// NewTokens creates tokens signed with secret that expire after expiry
+func NewTokens(secret []byte, expiry time.Duration) (*Tokens, error) {
+	if len(secret) < MinSecretLength {
+		return nil, fmt.Errorf("JWT secret must be at least %d bytes", MinSecretLength)
+	}
+	if expiry <= 0 {
+		return nil, fmt.Errorf("JWT expiry must be positive, got %s", expiry)
+	}
+	return &Tokens{secret: secret, expiry: expiry}, nil
+}
+
+// RandomSecret returns a signing key for development setups without one.
+// Tokens signed with it stop working when the process restarts.
+func RandomSecret() []byte {
+	secret := make([]byte, MinSecretLength)
+	_, _ = rand.Read(secret)
+	return secret
+}
+
+// Issue returns a token identifying userID and the time it expires
+func (t *Tokens) Issue(userID uint) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(t.expiry)
+	claims := jwt.RegisteredClaims{
+		Subject:   strconv.FormatUint(uint64(userID), 10),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// Verify checks a token's signature and expiry and returns its user ID.
+// Only HS256 is accepted, so a token can't pick a weaker algorithm.
+func (t *Tokens) Verify(token string) (uint, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims,
+		func(*jwt.Token) (interface{}, error) { return t.secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired())
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil || userID == 0 {
+		return 0, fmt.Errorf("%w: subject %q is not a user ID", ErrInvalidToken, claims.Subject)
+	}
+	return uint(userID), nil
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testSecret is a valid 32-byte signing key
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestIssuedTokensVerify(t *testing.T) {
+	tokens, err := NewTokens(testSecret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, expiresAt, err := tokens.Issue(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if until := time.Until(expiresAt); until <= 59*time.Minute || until > time.Hour {
+		t.Errorf("token expires in %s, want an hour", until)
+	}
+	if userID, err := tokens.Verify(token); err != nil || userID != 42 {
+		t.Fatalf("Verify() = %d, %v, want 42", userID, err)
+	}
+}
+
+func TestVerifyRejectsForeignTokens(t *testing.T) {
+	tokens, _ := NewTokens(testSecret, time.Hour)
+	valid, _, _ := tokens.Issue(42)
+	other, _ := NewTokens([]byte(strings.Repeat("x", MinSecretLength)), time.Hour)
+	foreign, _, _ := other.Issue(42)
+
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.RegisteredClaims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	expires := jwt.NewNumericDate(time.Now().Add(time.Hour))
+
+	tests := map[string]string{
+		"empty":        "",
+		"malformed":    "not.a.token",
+		"tampered":     valid[:len(valid)-2] + "xx",
+		"other key":    foreign,
+		"alg none":     sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, jwt.RegisteredClaims{Subject: "42", ExpiresAt: expires}),
+		"other alg":    sign(jwt.SigningMethodHS512, testSecret, jwt.RegisteredClaims{Subject: "42", ExpiresAt: expires}),
+		"expired":      sign(jwt.SigningMethodHS256, testSecret, jwt.RegisteredClaims{Subject: "42", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}),
+		"no expiry":    sign(jwt.SigningMethodHS256, testSecret, jwt.RegisteredClaims{Subject: "42"}),
+		"no subject":   sign(jwt.SigningMethodHS256, testSecret, jwt.RegisteredClaims{ExpiresAt: expires}),
+		"bad subject":  sign(jwt.SigningMethodHS256, testSecret, jwt.RegisteredClaims{Subject: "alice", ExpiresAt: expires}),
+		"zero subject": sign(jwt.SigningMethodHS256, testSecret, jwt.RegisteredClaims{Subject: "0", ExpiresAt: expires}),
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			if userID, err := tokens.Verify(token); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("Verify() = %d, %v, want ErrInvalidToken", userID, err)
+			}
+		})
+	}
+}
+
+func TestNewTokensRejectsWeakSettings(t *testing.T) {
+	if _, err := NewTokens([]byte("short"), time.Hour); err == nil {
+		t.Error("NewTokens accepted a 5-byte secret")
+	}
+	if _, err := NewTokens(testSecret, 0); err == nil {
+		t.Error("NewTokens accepted a zero expiry")
+	}
+	if _, err := NewTokens(RandomSecret(), time.Hour); err != nil {
+		t.Errorf("NewTokens(RandomSecret()) = %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"study-go-controller/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"gorm.io/gorm"
+)
+
+// Mode chooses how a batch handles failing items
+type Mode string
+
+const (
+	// Atomic applies every item in one transaction; any failure rolls all of them back
+	Atomic Mode = "atomic"
+	// BestEffort applies items independently and keeps the ones that succeed
+	BestEffort Mode = "best_effort"
+)
+
+// Options are the settings shared by batch request bodies; embed them
+// next to an Items field bound with `binding:"required,min=1,max=1000"`
+type Options struct {
+	Mode Mode `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+}
+
+// errItemFailed rolls back the transaction of an atomic batch, or the
+// savepoint of a best-effort item
+var errItemFailed = errors.New("batch item failed")
+
+// ItemResult reports the outcome of one item, by its index in the request
+type ItemResult struct {
+	Index  int         `json:"index"`
+	Status int         `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// Result is the response payload of batch endpoints
+type Result struct {
+	Mode      Mode         `json:"mode"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Items     []ItemResult `json:"items"`
+}
+
+// Run validates each item with its binding tags and passes it to apply.
+//
+// BestEffort calls transaction once per item, so a failing item only rolls
+// back its own writes. Inside a request's transaction each item gets a
+// savepoint, which also keeps a database error from aborting the whole
+// transaction on PostgreSQL. Atomic (the default) calls transaction once and
+// applies every item with the transaction-bound service it receives; the
+// first failure aborts the transaction, and the other items are reported as
+// rolled back or not applied.
+func Run[S, T any](mode Mode, items []T, service S, transaction func(func(S) error) error, apply func(S, T) (interface{}, error)) *Result {
+	if mode == "" {
+		mode = Atomic
+	}
+	result := &Result{Mode: mode, Items: make([]ItemResult, len(items))}
+
+	if mode == BestEffort {
+		for i, item := range items {
+			err := transaction(func(tx S) error {
+				result.Items[i] = runItem(i, item, tx, apply)
+				if result.Items[i].Error != "" {
+					return errItemFailed
+				}
+				return nil
+			})
+			if err != nil && result.Items[i].Error == "" {
+				// The item's savepoint or commit failed
+				result.Items[i] = ItemResult{Index: i, Status: http.StatusInternalServerError, Error: err.Error()}
+			}
+		}
+		return result.tally()
+	}
+
+	failed := -1
+	err := transaction(func(tx S) error {
+		for i, item := range items {
+			result.Items[i] = runItem(i, item, tx, apply)
+			if result.Items[i].Error != "" {
+				failed = i
+				return errItemFailed
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range result.Items {
+			switch {
+			case i == failed:
+			case failed < 0:
+				// The commit itself failed
+				result.Items[i] = ItemResult{Index: i, Status: http.StatusInternalServerError, Error: err.Error()}
+			case i < failed:
+				result.Items[i] = ItemResult{Index: i, Status: http.StatusFailedDependency,
+					Error: fmt.Sprintf("rolled back: item %d failed", failed)}
+			default:
+				result.Items[i] = ItemResult{Index: i, Status: http.StatusFailedDependency,
+					Error: fmt.Sprintf("not applied: item %d failed", failed)}
+			}
+		}
+	}
+	return result.tally()
+}
+
+// Respond sends the result: successStatus when every item succeeded, the
+// failing item's status when an atomic batch was rolled back, and
+// 207 Multi-Status when items of a best-effort batch failed, even all of
+// them, since each item carries its own status
+func Respond(c *gin.Context, successStatus int, message string, result *Result) {
+	status := successStatus
+	switch {
+	case result.Failed == 0:
+	case result.Mode == Atomic:
+		status = http.StatusFailedDependency
+		for _, item := range result.Items {
+			if item.Status != http.StatusFailedDependency {
+				status = item.Status
+				break
+			}
+		}
+	default:
+		status = http.StatusMultiStatus
+	}
+
+	if result.Failed > 0 {
+		message = fmt.Sprintf("%d of %d items failed", result.Failed, len(result.Items))
+	}
+	c.JSON(status, response.APIResponse{
+		Success: result.Failed == 0,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// runItem validates and applies a single item
+func runItem[S, T any](index int, item T, service S, apply func(S, T) (interface{}, error)) ItemResult {
+	if err := binding.Validator.ValidateStruct(item); err != nil {
+		return ItemResult{Index: index, Status: http.StatusBadRequest, Error: "Validation failed: " + err.Error()}
+	}
+
+	data, err := apply(service, item)
+	if err != nil {
+		status, message := errorStatus(err)
+		return ItemResult{Index: index, Status: status, Error: message}
+	}
+	return ItemResult{Index: index, Status: http.StatusOK, Data: data}
+}
+
+// errorStatus maps an item error like the typed handlers do: HTTPErrors keep
+// their status, missing records are 404, duplicates 409 and anything else
+// is a bad request
+func errorStatus(err error) (int, string) {
+	var httpErr *response.HTTPError
+	switch {
+	case errors.As(err, &httpErr):
+		return httpErr.Status, httpErr.Message
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound, "Resource not found"
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return http.StatusConflict, "Resource already exists"
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return http.StatusBadRequest, "Referenced resource does not exist"
+	default:
+		return http.StatusBadRequest, err.Error()
+	}
+}
+
+// tally counts succeeded and failed items
+func (r *Result) tally() *Result {
+	for _, item := range r.Items {
+		if item.Error == "" {
+			r.Succeeded++
+		} else {
+			r.Failed++
+		}
+	}
+	return r
+}
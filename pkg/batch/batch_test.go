@@ -0,0 +1,142 @@
+package batch
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type note struct {
+	ID   uint
+	Body string
+}
+
+type noteItem struct {
+	Body string `binding:"required"`
+	Fail bool   // fail after writing the row
+}
+
+// createNote writes the item, then fails if asked to, leaving a write behind
+func createNote(tx *gorm.DB, item noteItem) (interface{}, error) {
+	n := &note{Body: item.Body}
+	if err := tx.Create(n).Error; err != nil {
+		return nil, err
+	}
+	if item.Fail {
+		return nil, errors.New("failed after writing")
+	}
+	return n, nil
+}
+
+// openNotes returns an in-memory database with the notes table
+func openNotes(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&note{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// transaction adapts db.Transaction to Run, like the services' Transaction methods
+func transaction(db *gorm.DB) func(func(*gorm.DB) error) error {
+	return func(fn func(*gorm.DB) error) error { return db.Transaction(fn) }
+}
+
+// bodies lists the committed notes
+func bodies(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var notes []note
+	if err := db.Order("id").Find(&notes).Error; err != nil {
+		t.Fatal(err)
+	}
+	var list []string
+	for _, n := range notes {
+		list = append(list, n.Body)
+	}
+	return list
+}
+
+func TestBestEffortRollsBackOnlyFailedItemsInsideRequestTransaction(t *testing.T) {
+	db := openNotes(t)
+	items := []noteItem{{Body: "first"}, {Body: "broken", Fail: true}, {Body: ""}, {Body: "last"}}
+
+	// Like RequestScope, the whole batch runs inside one request transaction
+	request := db.Begin()
+	result := Run(BestEffort, items, request, transaction(request), createNote)
+	if err := request.Commit().Error; err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	if result.Succeeded != 2 || result.Failed != 2 {
+		t.Fatalf("succeeded/failed = %d/%d, want 2/2", result.Succeeded, result.Failed)
+	}
+	wantStatus := []int{http.StatusOK, http.StatusBadRequest, http.StatusBadRequest, http.StatusOK}
+	for i, item := range result.Items {
+		if item.Status != wantStatus[i] {
+			t.Errorf("item %d status = %d, want %d", i, item.Status, wantStatus[i])
+		}
+	}
+	if got := bodies(t, db); len(got) != 2 || got[0] != "first" || got[1] != "last" {
+		t.Fatalf("committed notes = %v, want [first last]", got)
+	}
+}
+
+func TestAtomicRollsBackEveryItem(t *testing.T) {
+	db := openNotes(t)
+	items := []noteItem{{Body: "first"}, {Body: "broken", Fail: true}, {Body: "last"}}
+
+	result := Run(Atomic, items, db, transaction(db), createNote)
+
+	wantStatus := []int{http.StatusFailedDependency, http.StatusBadRequest, http.StatusFailedDependency}
+	for i, item := range result.Items {
+		if item.Status != wantStatus[i] {
+			t.Errorf("item %d status = %d, want %d", i, item.Status, wantStatus[i])
+		}
+	}
+	if got := bodies(t, db); len(got) != 0 {
+		t.Fatalf("committed notes = %v, want none", got)
+	}
+}
+
+func TestRespondStatuses(t *testing.T) {
+	failed := ItemResult{Status: http.StatusBadRequest}
+	conflict := ItemResult{Status: http.StatusConflict}
+	ok := ItemResult{Status: http.StatusOK}
+	skipped := ItemResult{Status: http.StatusFailedDependency}
+
+	tests := []struct {
+		name   string
+		result Result
+		status int
+	}{
+		{"atomic success", Result{Mode: Atomic, Succeeded: 2, Items: []ItemResult{ok, ok}}, http.StatusCreated},
+		{"atomic failure", Result{Mode: Atomic, Failed: 2, Items: []ItemResult{skipped, conflict}}, http.StatusConflict},
+		{"best-effort success", Result{Mode: BestEffort, Succeeded: 2, Items: []ItemResult{ok, ok}}, http.StatusCreated},
+		{"best-effort partial failure", Result{Mode: BestEffort, Succeeded: 1, Failed: 1, Items: []ItemResult{ok, failed}}, http.StatusMultiStatus},
+		{"best-effort total failure", Result{Mode: BestEffort, Failed: 2, Items: []ItemResult{failed, failed}}, http.StatusMultiStatus},
+		{"best-effort mixed failures", Result{Mode: BestEffort, Failed: 2, Items: []ItemResult{failed, conflict}}, http.StatusMultiStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			Respond(c, http.StatusCreated, "created", &tt.result)
+			if recorder.Code != tt.status {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the application configuration
+type Config struct {
+	Port            string
+	ShutdownTimeout time.Duration // for draining requests and stopping components
+	TrustedProxies  []string      // IPs or CIDRs whose X-Forwarded-For is believed; none by default
+	AdminAddr       string        // listener of the internal endpoints, loopback by default; empty disables it
+	Database        DatabaseConfig
+	Auth            AuthConfig
+	CORS            CORSConfig
+	DisabledModules []string // module names switched off, see container.Module
+	MigrateOnStart  string   // check (refuse to start when migrations are pending), up or off
+}
+
+// AuthConfig holds the settings of the bearer tokens issued at login
+type AuthConfig struct {
+	// JWTSecret signs the tokens, at least 32 bytes; without one the
+	// container signs with a random key, so tokens don't survive a restart
+	JWTSecret string
+	JWTExpiry time.Duration
+}
+
+// DatabaseConfig holds the database connection settings
+type DatabaseConfig struct {
+	Driver   string // mysql, postgres or sqlite
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string // for sqlite, the database file or ":memory:"
+	SSLMode  string // postgres only
+
+	// Connection pool of database/sql; zero means no limit
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// ConnectTimeout bounds the retries of the startup connection
+	ConnectTimeout time.Duration
+	// MaxReplicationLag fails the readiness check of the replica when it
+	// lags further behind; zero, the default, disables the check, which needs
+	// REPLICATION CLIENT or REPLICA MONITOR on MySQL
+	MaxReplicationLag time.Duration
+
+	// Replica is a read replica from DB_REPLICA_HOST, or nil; it shares the
+	// primary's settings other than host and port
+	Replica *DatabaseConfig
+}
+
+// ErrCredentialsWithAnyOrigin rejects AllowCredentials combined with the "*"
+// origin, which would let every site make requests with the user's credentials
+var ErrCredentialsWithAnyOrigin = errors.New(`CORS: AllowCredentials can't be combined with the "*" origin; list the trusted origins instead`)
+
+// CORSConfig controls cross-origin access, see middleware.CORS; CORS is
+// disabled without AllowedOrigins
+type CORSConfig struct {
+	// AllowedOrigins are exact origins, "*", or wildcards like "https://*.example.com"
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string // "*" echoes whatever the preflight requests
+	ExposedHeaders   []string
+	AllowCredentials bool // not with the "*" origin, see Validate
+	MaxAge           time.Duration
+}
+
+// Validate rejects settings that would expose credentials to every origin
+func (cfg CORSConfig) Validate() error {
+	if cfg.AllowsAnyOrigin() && cfg.AllowCredentials {
+		return ErrCredentialsWithAnyOrigin
+	}
+	return nil
+}
+
+// Enabled reports whether any origin is allowed
+func (cfg CORSConfig) Enabled() bool {
+	return len(cfg.AllowedOrigins) > 0
+}
+
+// AllowsAnyOrigin reports whether every origin is allowed
+func (cfg CORSConfig) AllowsAnyOrigin() bool {
+	return slices.Contains(cfg.AllowedOrigins, "*")
+}
+
+// defaultDBPorts and defaultDBNames apply when DB_PORT and DB_NAME are unset
+var (
+	defaultDBPorts = map[string]string{"mysql": "3306", "postgres": "5432"}
+	defaultDBNames = map[string]string{
+		"mysql":    "study_go_controller",
+		"postgres": "study_go_controller",
+		"sqlite":   "study_go_controller.db",
+	}
+)
+
+// Load reads the configuration from environment variables, with defaults
+// for local development; see configs/config.example
+func Load() *Config {
+	driver := getEnv("DB_DRIVER", "mysql")
+	cfg := &Config{
+		Port:            getEnv("PORT", "8080"),
+		ShutdownTimeout: getDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		TrustedProxies:  getList("TRUSTED_PROXIES", ""),
+		AdminAddr:       getEnv("ADMIN_ADDR", "127.0.0.1:9090"),
+		Database: DatabaseConfig{
+			Driver:            driver,
+			Host:              getEnv("DB_HOST", "localhost"),
+			Port:              getEnv("DB_PORT", defaultDBPorts[driver]),
+			User:              getEnv("DB_USER", "root"),
+			Password:          getEnv("DB_PASSWORD", ""),
+			Name:              getEnv("DB_NAME", defaultDBNames[driver]),
+			SSLMode:           getEnv("DB_SSLMODE", "disable"),
+			MaxOpenConns:      getInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:      getInt("DB_MAX_IDLE_CONNS", 10),
+			ConnMaxLifetime:   getDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+			ConnMaxIdleTime:   getDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+			ConnectTimeout:    getDuration("DB_CONNECT_TIMEOUT", 30*time.Second),
+			MaxReplicationLag: getDuration("DB_MAX_REPLICATION_LAG", 0),
+		},
+		Auth: AuthConfig{
+			JWTSecret: getEnv("JWT_SECRET", ""),
+			JWTExpiry: getDuration("JWT_EXPIRY", 24*time.Hour),
+		},
+		CORS:            loadCORS(),
+		DisabledModules: getList("MODULES_DISABLED", ""),
+		MigrateOnStart:  getEnv("MIGRATE_ON_START", "check"),
+	}
+	if host := os.Getenv("DB_REPLICA_HOST"); host != "" {
+		replica := cfg.Database
+		replica.Host = host
+		if port := os.Getenv("DB_REPLICA_PORT"); port != "" {
+			replica.Port = port
+		}
+		cfg.Database.Replica = &replica
+	}
+	return cfg
+}
+
+// loadCORS reads the CORS_* environment variables, e.g.
+//
+//	CORS_ALLOWED_ORIGINS=https://app.example.com,https://*.example.com
+//	CORS_ALLOW_CREDENTIALS=true
+//	CORS_MAX_AGE=10m
+func loadCORS() CORSConfig {
+	cfg := CORSConfig{
+		AllowedOrigins: getList("CORS_ALLOWED_ORIGINS", ""),
+		AllowedMethods: getList("CORS_ALLOWED_METHODS", "GET,HEAD,POST,PUT,PATCH,DELETE"),
+		AllowedHeaders: getList("CORS_ALLOWED_HEADERS", "Content-Type,Authorization"),
+		// Deprecation notices and rate limiting are reported through headers
+		ExposedHeaders: getList("CORS_EXPOSED_HEADERS", "Deprecation,Sunset,Link,Retry-After"),
+		MaxAge:         getDuration("CORS_MAX_AGE", 10*time.Minute),
+	}
+	cfg.AllowCredentials, _ = strconv.ParseBool(os.Getenv("CORS_ALLOW_CREDENTIALS"))
+	return cfg
+}
+
+// getEnv gets environment variable with fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
+
+// getInt reads an integer environment variable, keeping fallback when it
+// is unset or invalid
+func getInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
+}
+
+// getDuration reads a duration such as "30s", keeping fallback when it is
+// unset or invalid
+func getDuration(key string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
+}
+
+// getList reads a comma-separated environment variable with fallback; set
+// to an empty value, it is an empty list
+func getList(key, fallback string) []string {
+	var items []string
+	for _, item := range strings.Split(getEnv(key, fallback), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
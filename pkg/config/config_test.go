@@ -0,0 +1,38 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReplicationLagCheckIsOffByDefault(t *testing.T) {
+	t.Setenv("DB_REPLICA_HOST", "replica.internal")
+
+	cfg := Load()
+	if cfg.Database.Replica == nil {
+		t.Fatal("DB_REPLICA_HOST didn't configure a replica")
+	}
+	if cfg.Database.MaxReplicationLag != 0 || cfg.Database.Replica.MaxReplicationLag != 0 {
+		t.Fatalf("MaxReplicationLag = %s/%s, want the check disabled",
+			cfg.Database.MaxReplicationLag, cfg.Database.Replica.MaxReplicationLag)
+	}
+
+	t.Setenv("DB_MAX_REPLICATION_LAG", "5s")
+	if lag := Load().Database.Replica.MaxReplicationLag; lag != 5*time.Second {
+		t.Fatalf("replica MaxReplicationLag = %s, want 5s", lag)
+	}
+}
+
+func TestCORSConfigRejectsCredentialsWithAnyOrigin(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "*")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	if err := Load().CORS.Validate(); !errors.Is(err, ErrCredentialsWithAnyOrigin) {
+		t.Fatalf("Validate() = %v, want %v", err, ErrCredentialsWithAnyOrigin)
+	}
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+	if err := Load().CORS.Validate(); err != nil {
+		t.Fatalf("Validate() with a listed origin = %v", err)
+	}
+}
@@ -1,135 +1,462 @@
 package container
 
 import (
+	"fmt"
 	"log"
+	"net/http"
 	"reflect"
+	"sort"
 	"strings"
+	"study-go-controller/pkg/config"
+	"study-go-controller/pkg/middleware"
+	"study-go-controller/pkg/response"
+	"study-go-controller/pkg/routing"
 
 	"github.com/gin-gonic/gin"
 )
 
-// RouteInfo holds information about a route
+// ginContextType is the parameter type every route method must accept
+var ginContextType = reflect.TypeOf((*gin.Context)(nil))
+
+// RouteInfo holds information about a route.
+// Use RouteDescriptor for a serializable view.
 type RouteInfo struct {
 	Method      string
 	Path        string
-	HandlerFunc gin.HandlerFunc
-	Middleware  []gin.HandlerFunc
+	HandlerName string
+	MethodName  string
+	HandlerFunc gin.HandlerFunc   `json:"-"`
+	Middleware  []gin.HandlerFunc `json:"-"`
+
+	// Path parameter names, and the subset identifying parent resources
+	// (e.g. [id] for /posts/:id/comments/:commentId)
+	Params       []string
+	ParentParams []string
+	ParamTypes   map[string]routing.ParamType
+
+	// API version the route is served in, and its deprecation notice if any
+	Version     string
+	Deprecation *routing.Deprecation
+
+	// Message sent with successful responses of typed handler methods, and
+	// the status of successful responses, see defaultSuccessStatus
+	SuccessMessage string
+	SuccessStatus  int
+
+	// Documentation metadata declared through routing.RouteSpec
+	Summary      string
+	RequestType  reflect.Type
+	ResponseType reflect.Type
+
+	// handlerType links the route to its handler in the dependency graph
+	handlerType reflect.Type
 }
 
 // AutoRouter handles automatic route registration
 type AutoRouter struct {
-	routes []RouteInfo
+	routes     []RouteInfo
+	versions   []APIVersion
+	removals   []routeRemoval
+	convention routing.RouteConvention
+	cors       config.CORSConfig
+	factory    *Factory
 }
 
-// NewAutoRouter creates a new auto router
+// NewAutoRouter creates a new auto router serving the default API version
 func NewAutoRouter() *AutoRouter {
 	return &AutoRouter{
-		routes: make([]RouteInfo, 0),
+		routes:     make([]RouteInfo, 0),
+		versions:   []APIVersion{{Name: defaultVersion}},
+		convention: routing.RESTConvention{},
 	}
 }
 
-// RegisterHandler automatically registers all routes for a handler
-func (ar *AutoRouter) RegisterHandler(basePath string, handler interface{}) {
+// SetDefaultConvention changes the convention used for handlers that don't choose one
+func (ar *AutoRouter) SetDefaultConvention(convention routing.RouteConvention) {
+	ar.convention = convention
+}
+
+// SetCORS enables CORS headers and preflight handling for every version group
+func (ar *AutoRouter) SetCORS(cfg config.CORSConfig) {
+	ar.cors = cfg
+}
+
+// SetFactory lets handlers provided with AsScoped run per request: each
+// request calls the route method on the instance built in its RequestScope
+func (ar *AutoRouter) SetFactory(factory *Factory) {
+	ar.factory = factory
+}
+
+// RegisterHandler automatically registers all routes for a handler.
+// Routes declared through routing.RouteProvider take precedence; every other
+// method is mapped by naming convention.
+//
+// Middleware runs in a fixed order: routing.MiddlewareProvider, WithMiddleware,
+// WithMethodMiddleware, then middleware declared on the RouteSpec.
+func (ar *AutoRouter) RegisterHandler(basePath string, handler interface{}, opts ...HandlerOption) error {
 	handlerType := reflect.TypeOf(handler)
-	handlerValue := reflect.ValueOf(handler)
+	handlerName := handlerType.Elem().Name()
+	cfg := newHandlerConfig(opts)
+
+	for methodName := range cfg.methodMiddleware {
+		if _, exists := handlerType.MethodByName(methodName); !exists {
+			return fmt.Errorf("middleware registered for unknown method %s.%s", handlerName, methodName)
+		}
+	}
+
+	// Convention precedence: WithConvention, routing.ConventionProvider, router default
+	convention := ar.convention
+	if provider, ok := handler.(routing.ConventionProvider); ok {
+		convention = provider.RouteConvention()
+	}
+	if cfg.convention != nil {
+		convention = cfg.convention
+	}
+	conventionCtx := routing.ConventionContext{
+		HandlerName: handlerName,
+		BasePath:    basePath,
+		Resource:    cfg.resource,
+		Singletons:  cfg.singletons,
+	}
+	if conventionCtx.Resource == "" {
+		conventionCtx.Resource = routing.ResourceName(basePath)
+	}
+
+	// Nested handlers are mounted below their parent's item path
+	if cfg.parentPath != "" {
+		conventionCtx.Parent = routing.ResourceName(cfg.parentPath)
+		basePath = cfg.parentPath + "/:" + cfg.parentParam + basePath
+	}
+
+	// Handler-wide middleware chain
+	var handlerMiddleware []gin.HandlerFunc
+	if provider, ok := handler.(routing.MiddlewareProvider); ok {
+		handlerMiddleware = append(handlerMiddleware, provider.Middleware()...)
+	}
+	handlerMiddleware = append(handlerMiddleware, cfg.middleware...)
+
+	// Scoped handlers are resolved again for every request
+	scoped := false
+	if ar.factory != nil {
+		lifetime, ok := ar.factory.Lifetime(handlerType)
+		scoped = ok && lifetime == Scoped
+	}
+	var bindings map[string]routing.Binding
+	if provider, ok := handler.(routing.BindingProvider); ok {
+		bindings = provider.RouteBindings()
+	}
+
+	// Collect explicitly declared routes by method name
+	declared := make(map[string][]routing.RouteSpec)
+	if provider, ok := handler.(routing.RouteProvider); ok {
+		for _, spec := range provider.Routes() {
+			if spec.Remove {
+				ar.removals = append(ar.removals, routeRemoval{
+					version:     versionOr(spec.Version, cfg.version),
+					method:      spec.Method,
+					path:        basePath + spec.Path,
+					handlerName: handlerName,
+				})
+				continue
+			}
+
+			method, exists := handlerType.MethodByName(spec.Action)
+			if !exists {
+				return fmt.Errorf("%s declares a route for unknown method %s",
+					handlerName, spec.Action)
+			}
+			if !isRouteMethod(method) {
+				return fmt.Errorf("%s.%s must be func(*gin.Context) or "+
+					"func(context.Context[, *Request]) ([*Response, ]error) to be routed",
+					handlerName, spec.Action)
+			}
+			declared[spec.Action] = append(declared[spec.Action], spec)
+		}
+	}
 
 	// Iterate through all methods of the handler
 	for i := 0; i < handlerType.NumMethod(); i++ {
 		method := handlerType.Method(i)
-		methodValue := handlerValue.Method(i)
+		if !isRouteMethod(method) {
+			continue
+		}
+
+		var routes []RouteInfo
+		if specs, ok := declared[method.Name]; ok {
+			for _, spec := range specs {
+				route, err := routeFromSpec(spec, convention, conventionCtx)
+				if err != nil {
+					return fmt.Errorf("%s.%s: %w", handlerName, method.Name, err)
+				}
+				routes = append(routes, route)
+			}
+		} else if route := conventionRoute(convention, conventionCtx, method.Name); route != nil {
+			routes = append(routes, *route)
+		}
 
-		// Check if method has route tag
-		if route := ar.parseMethodName(method.Name); route != nil {
-			// Create gin.HandlerFunc wrapper
-			handlerFunc := ar.createHandlerFunc(methodValue)
+		methodMiddleware := append(append([]gin.HandlerFunc{}, handlerMiddleware...),
+			cfg.methodMiddleware[method.Name]...)
 
+		for _, route := range routes {
 			route.Path = basePath + route.Path
-			route.HandlerFunc = handlerFunc
+			route.Params, route.ParentParams = routeParams(route.Path)
+			route.ParamTypes = paramTypes(route.Params, route.ParamTypes, cfg.params)
+			route.Version = versionOr(route.Version, cfg.version)
+			route.HandlerName = handlerName
+			route.handlerType = handlerType
+			route.MethodName = method.Name
+			if route.SuccessStatus == 0 {
+				route.SuccessStatus = defaultSuccessStatus(route.Method, method.Name)
+			}
+
+			// Typed methods document their own request and response types
+			if request, response, typed := typedSignature(method.Type); typed {
+				if route.RequestType == nil && request != nil {
+					route.RequestType = request.Elem()
+				}
+				if route.ResponseType == nil && response != nil {
+					route.ResponseType = response
+				}
+			}
+
+			// The call is bound once; scoped routes only swap the instance per request
+			call := bindRoute(bindings, method, route)
+			route.HandlerFunc = func(c *gin.Context) { call(handler, c) }
+			if scoped {
+				route.HandlerFunc = scopedHandlerFunc(handlerType, handler, call, route)
+			}
+			route.Middleware = append(append([]gin.HandlerFunc{}, methodMiddleware...), route.Middleware...)
 
-			ar.routes = append(ar.routes, *route)
-			log.Printf("🔗 Auto-registered route: %s %s -> %s.%s",
-				route.Method, route.Path, handlerType.Elem().Name(), method.Name)
+			ar.routes = append(ar.routes, route)
+			log.Printf("🔗 Auto-registered route: [%s] %s %s -> %s.%s",
+				route.Version, route.Method, route.Path, handlerName, method.Name)
 		}
 	}
-}
-
-// parseMethodName converts method name to route info using convention
-func (ar *AutoRouter) parseMethodName(methodName string) *RouteInfo {
-	// Convention-based routing:
-	// CreateUser -> POST /
-	// GetUser -> GET /:id
-	// GetAllUsers -> GET /
-	// UpdateUser -> PUT /:id
-	// DeleteUser -> DELETE /:id
-	// GetUserProfile -> GET /:id/profile
-	// ChangePassword -> PUT /:id/password
 
-	route := &RouteInfo{}
+	return nil
+}
 
-	switch {
-	case strings.HasPrefix(methodName, "Create"):
-		route.Method = "POST"
-		route.Path = ""
+// routeFromSpec builds route info from a declaration. Specs without a
+// method only carry metadata and take their method and path from the convention.
+func routeFromSpec(spec routing.RouteSpec, convention routing.RouteConvention, ctx routing.ConventionContext) (RouteInfo, error) {
+	route := RouteInfo{Method: spec.Method, Path: spec.Path}
+	if spec.Method == "" {
+		conventional := conventionRoute(convention, ctx, spec.Action)
+		if conventional == nil {
+			return route, fmt.Errorf("no conventional route; declare a method and path")
+		}
+		route = *conventional
+	}
 
-	case strings.HasPrefix(methodName, "GetAll"):
-		route.Method = "GET"
-		route.Path = ""
+	route.Version = spec.Version
+	route.Deprecation = spec.Deprecation
+	route.Summary = spec.Summary
+	route.SuccessMessage = spec.SuccessMessage
+	route.SuccessStatus = spec.SuccessStatus
+	route.Middleware = spec.Middleware
+	route.ParamTypes = spec.Params
+	if spec.Request != nil {
+		route.RequestType = reflect.TypeOf(spec.Request)
+	}
+	if spec.Response != nil {
+		route.ResponseType = reflect.TypeOf(spec.Response)
+	}
+	return route, nil
+}
 
-	case strings.HasPrefix(methodName, "Get") && strings.Contains(methodName, "Profile"):
-		route.Method = "GET"
-		route.Path = "/:id/profile"
+// defaultSuccessStatus is 201 Created for POST routes of Create* methods
+// and 200 OK for everything else, including actions like batch updates
+func defaultSuccessStatus(method, methodName string) int {
+	if method == http.MethodPost && strings.HasPrefix(methodName, "Create") {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
 
-	case strings.HasPrefix(methodName, "Get"):
-		route.Method = "GET"
-		route.Path = "/:id"
+// isRouteMethod reports whether a method can serve as a route:
+// either a plain gin handler or a typed handler method
+func isRouteMethod(method reflect.Method) bool {
+	if isGinMethod(method.Type) {
+		return true
+	}
+	_, _, typed := typedSignature(method.Type)
+	return typed
+}
 
-	case strings.HasPrefix(methodName, "Update"):
-		route.Method = "PUT"
-		route.Path = "/:id"
+// isGinMethod reports whether a method is a plain gin handler
+func isGinMethod(methodType reflect.Type) bool {
+	// Receiver plus *gin.Context, no return values
+	return methodType.NumIn() == 2 &&
+		methodType.In(1) == ginContextType &&
+		methodType.NumOut() == 0
+}
 
-	case strings.HasPrefix(methodName, "Delete"):
-		route.Method = "DELETE"
-		route.Path = "/:id"
+// routeParams lists a path's params and the ones that belong to parent
+// resources: every param except one in the final segment
+func routeParams(path string) (params, parents []string) {
+	segments := splitPath(path)
+	for i, segment := range segments {
+		if segmentKind(segment) == 0 {
+			continue
+		}
+		params = append(params, segment[1:])
+		if i < len(segments)-1 {
+			parents = append(parents, segment[1:])
+		}
+	}
+	return params, parents
+}
 
-	case strings.HasPrefix(methodName, "Change") && strings.Contains(methodName, "Password"):
-		route.Method = "PUT"
-		route.Path = "/:id/password"
+// paramTypes resolves the type of each path param: declared on the
+// route, then on the handler, then routing.DefaultParamType
+func paramTypes(params []string, declared ...map[string]routing.ParamType) map[string]routing.ParamType {
+	types := make(map[string]routing.ParamType, len(params))
+	for _, name := range params {
+		types[name] = routing.DefaultParamType(name)
+		for i := len(declared) - 1; i >= 0; i-- {
+			if paramType, ok := declared[i][name]; ok {
+				types[name] = paramType
+			}
+		}
+	}
+	return types
+}
 
-	default:
+// conventionRoute maps a method name to route info using a convention
+func conventionRoute(convention routing.RouteConvention, ctx routing.ConventionContext, methodName string) *RouteInfo {
+	method, path, ok := convention.Route(ctx, methodName)
+	if !ok {
 		// Not a route method
 		return nil
 	}
+	return &RouteInfo{Method: method, Path: path}
+}
 
-	return route
+// routeCall calls a route method on a handler instance
+type routeCall func(handler interface{}, c *gin.Context)
+
+// bindRoute resolves how a route calls its method, preferring the generated
+// bindings (cmd/routegen) over reflective calls
+func bindRoute(bindings map[string]routing.Binding, method reflect.Method, route RouteInfo) routeCall {
+	if binding, ok := bindings[method.Name]; ok {
+		switch {
+		case binding.Gin != nil:
+			return binding.Gin
+		case binding.Typed != nil:
+			return renderTyped(route, binding.NewRequest, binding.Typed)
+		}
+	}
+	if !isGinMethod(method.Type) {
+		return typedCall(method, route)
+	}
+	return func(handler interface{}, c *gin.Context) {
+		// method.Func takes the receiver as its first argument
+		method.Func.Call([]reflect.Value{reflect.ValueOf(handler), reflect.ValueOf(c)})
+	}
 }
 
-// createHandlerFunc creates a gin.HandlerFunc from a method
-func (ar *AutoRouter) createHandlerFunc(methodValue reflect.Value) gin.HandlerFunc {
+// scopedHandlerFunc makes call on the handler built in the request's scope,
+// falling back to the registered instance outside one
+func scopedHandlerFunc(handlerType reflect.Type, fallback interface{}, call routeCall, route RouteInfo) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Call the handler method with gin.Context
-		methodValue.Call([]reflect.Value{reflect.ValueOf(c)})
+		handler := fallback
+		if scope := ScopeFrom(c); scope != nil {
+			var err error
+			if handler, err = scope.Get(handlerType); err != nil {
+				log.Printf("❌ Failed to resolve %s for %s %s: %v", handlerType, route.Method, route.Path, err)
+				response.ErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+				return
+			}
+		}
+		call(handler, c)
 	}
 }
 
-// RegisterRoutes registers all collected routes to a router group
+// RegisterRoutes mounts every API version as a subgroup (/<version>) of routerGroup.
+// GET routes are also served for HEAD, and every path answers OPTIONS with the
+// methods it actually has.
 func (ar *AutoRouter) RegisterRoutes(routerGroup *gin.RouterGroup) {
-	for _, route := range ar.routes {
+	groups := make(map[string]*gin.RouterGroup)
+	for _, version := range ar.versions {
+		groups[version.Name] = routerGroup.Group("/"+version.Name, middleware.CORS(ar.cors))
+	}
+
+	routes := ar.GetRoutes()
+	for _, route := range routes {
+		// Route middleware runs after the group's, right before the handler
+		var chain []gin.HandlerFunc
+		if route.Deprecation != nil {
+			chain = append(chain, deprecationHeaders(route.Deprecation))
+		}
+		if len(route.ParamTypes) > 0 {
+			chain = append(chain, routing.ValidateParams(route.ParamTypes))
+		}
+		chain = append(chain, route.Middleware...)
+		chain = append(chain, route.HandlerFunc)
+
+		routerGroup := groups[route.Version]
 		switch route.Method {
 		case "GET":
-			routerGroup.GET(route.Path, route.HandlerFunc)
+			routerGroup.GET(route.Path, chain...)
+			routerGroup.HEAD(route.Path, chain...)
 		case "POST":
-			routerGroup.POST(route.Path, route.HandlerFunc)
+			routerGroup.POST(route.Path, chain...)
 		case "PUT":
-			routerGroup.PUT(route.Path, route.HandlerFunc)
+			routerGroup.PUT(route.Path, chain...)
 		case "DELETE":
-			routerGroup.DELETE(route.Path, route.HandlerFunc)
+			routerGroup.DELETE(route.Path, chain...)
 		case "PATCH":
-			routerGroup.PATCH(route.Path, route.HandlerFunc)
+			routerGroup.PATCH(route.Path, chain...)
+		}
+	}
+
+	for _, path := range pathMethods(routes) {
+		groups[path.version].OPTIONS(path.path, middleware.Preflight(ar.cors, path.methods))
+	}
+}
+
+// servedPath is a path of one API version with the methods it answers
+type servedPath struct {
+	version string
+	path    string
+	methods []string
+}
+
+// pathMethods groups routes by version and path, in registration order,
+// adding the implicit HEAD (for GET) and OPTIONS methods
+func pathMethods(routes []RouteInfo) []servedPath {
+	var paths []servedPath
+	index := make(map[string]int)
+	for _, route := range routes {
+		key := route.Version + " " + route.Path
+		i, exists := index[key]
+		if !exists {
+			i = len(paths)
+			index[key] = i
+			paths = append(paths, servedPath{version: route.Version, path: route.Path})
+		}
+		paths[i].methods = append(paths[i].methods, route.Method)
+		if route.Method == http.MethodGet {
+			paths[i].methods = append(paths[i].methods, http.MethodHead)
 		}
 	}
+
+	for i := range paths {
+		paths[i].methods = append(paths[i].methods, http.MethodOptions)
+		sort.Strings(paths[i].methods)
+	}
+	return paths
 }
 
-// GetRoutes returns all registered routes
+// GetRoutes returns the effective routes of every API version
 func (ar *AutoRouter) GetRoutes() []RouteInfo {
-	return ar.routes
+	return ar.resolveRoutes()
+}
+
+// versionOr returns version, or fallback when it is empty
+func versionOr(version, fallback string) string {
+	if version != "" {
+		return version
+	}
+	return fallback
 }
@@ -0,0 +1,157 @@
+package container
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"study-go-controller/pkg/routing"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// itemRequest is the request of the typed route
+type itemRequest struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// itemHandler has no generated bindings, so AutoRouter calls it by reflection
+type itemHandler struct{}
+
+func (h *itemHandler) Routes() []routing.RouteSpec {
+	return []routing.RouteSpec{
+		routing.DELETE("/:id", "Remove"),
+		routing.GET("/:id", "Show"),
+	}
+}
+
+func (h *itemHandler) Remove(c *gin.Context) { c.Status(http.StatusNoContent) }
+
+func (h *itemHandler) Show(ctx context.Context, req *itemRequest) (*itemRequest, error) {
+	return req, nil
+}
+
+// boundItemHandler serves the same routes through bindings shaped like
+// the ones cmd/routegen writes
+type boundItemHandler struct{ itemHandler }
+
+func (*boundItemHandler) RouteBindings() map[string]routing.Binding {
+	return map[string]routing.Binding{
+		"Remove": {Gin: func(h interface{}, c *gin.Context) { h.(*boundItemHandler).Remove(c) }},
+		"Show": {
+			Typed: func(h interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+				resp, err := h.(*boundItemHandler).Show(ctx, req.(*itemRequest))
+				return resp, err
+			},
+			NewRequest: func() interface{} { return new(itemRequest) },
+		},
+	}
+}
+
+// itemRoutes registers handler and returns its route handlers by method name
+func itemRoutes(tb testing.TB, handler interface{}) map[string]gin.HandlerFunc {
+	tb.Helper()
+	router := NewAutoRouter()
+	if err := router.RegisterHandler("/items", handler); err != nil {
+		tb.Fatal(err)
+	}
+	routes := make(map[string]gin.HandlerFunc)
+	for _, route := range router.GetRoutes() {
+		routes[route.MethodName] = route.HandlerFunc
+	}
+	return routes
+}
+
+// itemContext returns a context for a request to /items/7
+func itemContext() (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/items/7", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	return c, recorder
+}
+
+func TestBindingsAndReflectionServeTheSameResponses(t *testing.T) {
+	for name, handler := range map[string]interface{}{"reflective": &itemHandler{}, "generated": &boundItemHandler{}} {
+		routes := itemRoutes(t, handler)
+
+		c, recorder := itemContext()
+		routes["Show"](c)
+		if recorder.Code != http.StatusOK || recorder.Body.String() != `{"success":true,"message":"OK","data":{"ID":7}}` {
+			t.Errorf("%s Show = %d %s", name, recorder.Code, recorder.Body)
+		}
+
+		c, recorder = itemContext()
+		routes["Remove"](c)
+		if c.Writer.Status() != http.StatusNoContent {
+			t.Errorf("%s Remove = %d, want 204", name, recorder.Code)
+		}
+	}
+}
+
+// BenchmarkRouteBindings compares calling route methods through generated
+// bindings with the reflective fallback, for a gin and a typed method
+func BenchmarkRouteBindings(b *testing.B) {
+	handlers := []struct {
+		name    string
+		handler interface{}
+	}{
+		{"reflective", &itemHandler{}},
+		{"generated", &boundItemHandler{}},
+	}
+	for _, method := range []string{"Remove", "Show"} {
+		for _, h := range handlers {
+			handlerFunc := itemRoutes(b, h.handler)[method]
+			b.Run(method+"/"+h.name, func(b *testing.B) {
+				c, recorder := itemContext()
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					recorder.Body.Reset()
+					handlerFunc(c)
+				}
+			})
+		}
+	}
+}
+
+// commentHandler is mounted under posts with WithParent
+type commentHandler struct{}
+
+func (h *commentHandler) GetPostComments(c *gin.Context)     {}
+func (h *commentHandler) GetComment(c *gin.Context)          {}
+func (h *commentHandler) GetCommentReplies(c *gin.Context)   {}
+func (h *commentHandler) GetCommentsByAuthor(c *gin.Context) {}
+
+func TestNestedRoutesReportParentParams(t *testing.T) {
+	router := NewAutoRouter()
+	if err := router.RegisterHandler("/comments", &commentHandler{}, WithParent("/posts", "postId")); err != nil {
+		t.Fatal(err)
+	}
+
+	type nested struct {
+		path         string
+		params       []string
+		parentParams []string
+	}
+	want := map[string]nested{
+		"GetPostComments":   {"/posts/:postId/comments", []string{"postId"}, []string{"postId"}},
+		"GetComment":        {"/posts/:postId/comments/:id", []string{"postId", "id"}, []string{"postId"}},
+		"GetCommentReplies": {"/posts/:postId/comments/:id/replies", []string{"postId", "id"}, []string{"postId", "id"}},
+	}
+	routes := router.GetRoutes()
+	if len(routes) != len(want) {
+		t.Fatalf("registered %d routes, want %d; GetCommentsByAuthor needs a declaration", len(routes), len(want))
+	}
+	for _, route := range routes {
+		w, ok := want[route.MethodName]
+		if !ok {
+			t.Errorf("unexpected route %s %s -> %s", route.Method, route.Path, route.MethodName)
+			continue
+		}
+		if route.Path != w.path || !equalStrings(route.Params, w.params) || !equalStrings(route.ParentParams, w.parentParams) {
+			t.Errorf("%s = %s params %v parents %v, want %s params %v parents %v", route.MethodName,
+				route.Path, route.Params, route.ParentParams, w.path, w.params, w.parentParams)
+		}
+	}
+}
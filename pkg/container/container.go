@@ -1,108 +1,279 @@
 package container
 
 import (
+	"fmt"
 	"log"
-	"study-go-controller/internal/domain/post/handler"
-	postRepo "study-go-controller/internal/domain/post/repository"
-	postService "study-go-controller/internal/domain/post/service"
-	userHandler "study-go-controller/internal/domain/user/handler"
-	userRepo "study-go-controller/internal/domain/user/repository"
-	userService "study-go-controller/internal/domain/user/service"
+	"reflect"
+	"study-go-controller/pkg/auth"
+	"study-go-controller/pkg/config"
 	"study-go-controller/pkg/database"
+	"study-go-controller/pkg/middleware"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// apiPrefix is the path prefix API versions are mounted under (/api/v1, /api/v2, ...)
+const apiPrefix = "/api"
+
 // Container holds all dependencies
 type Container struct {
-	DB *gorm.DB
-
-	// Repositories
-	UserRepo userRepo.UserRepository
-	PostRepo postRepo.PostRepository
+	DB     *gorm.DB
+	Config *config.Config
 
-	// Services
-	UserService userService.UserService
-	PostService postService.PostService
+	// Tokens verifies the bearer tokens of API requests
+	Tokens *auth.Tokens
 
-	// Handlers
-	UserHandler *userHandler.UserHandler
-	PostHandler *handler.PostHandler
+	// Modules are the enabled domains the container was assembled from
+	Modules []Module
 
 	// Auto Router
 	AutoRouter *AutoRouter
+
+	// Factory resolves the modules' providers by type
+	Factory *Factory
+
+	// Registry holds every component the factory built, for lookups by
+	// type or interface, see Resolve and ResolveAll
+	Registry *ServiceRegistry
 }
 
-// NewContainer creates and initializes all dependencies
-func NewContainer() (*Container, error) {
-	// Initialize database
-	db, err := database.NewDatabase()
+// NewContainer creates and initializes all dependencies. Without options it
+// loads the configuration from the environment, connects to the configured
+// database and checks its migrations; options swap any of that out for tests.
+func NewContainer(opts ...Option) (*Container, error) {
+	o := newOptions(opts)
+	modules, err := EnabledModules(o.config.DisabledModules)
 	if err != nil {
 		return nil, err
 	}
 
-	// Run migrations
-	if err := db.AutoMigrate(); err != nil {
+	// Initialize database, unless the caller brought one
+	db := &database.Database{DB: o.db}
+	var replica *database.Database
+	if o.db == nil {
+		if db, err = database.NewDatabase(o.config.Database); err != nil {
+			return nil, err
+		}
+		if replicaConfig := o.config.Database.Replica; replicaConfig != nil {
+			if replica, err = database.NewDatabase(*replicaConfig); err != nil {
+				db.Close()
+				return nil, fmt.Errorf("replica: %w", err)
+			}
+			replica.Role = "replica"
+			// Only the replica's lag is checked; the primary doesn't replicate
+			replica.MaxReplicationLag = replicaConfig.MaxReplicationLag
+		}
+	}
+
+	// Check or apply the modules' migrations
+	if o.migrations {
+		if err := migrateOnStart(db.DB, modules, o.config.MigrateOnStart); err != nil {
+			return nil, err
+		}
+	}
+
+	return newContainer(db, replica, o.db == nil, modules, o)
+}
+
+// NewRouteContainer builds the handler graph without a database connection.
+// Only the route table is usable; it backs tooling such as the OpenAPI export.
+func NewRouteContainer(opts ...Option) (*Container, error) {
+	o := newOptions(opts)
+	modules, err := EnabledModules(o.config.DisabledModules)
+	if err != nil {
 		return nil, err
 	}
+	return newContainer(&database.Database{}, nil, false, modules, o)
+}
 
-	// Initialize repositories
-	userRepository := userRepo.NewUserRepository(db.DB)
-	postRepository := postRepo.NewPostRepository(db.DB)
+// newContainer wires the modules' repositories, services and handlers on top
+// of db and the optional read replica; the container closes them on Stop only
+// when it owns them
+func newContainer(db, replica *database.Database, ownsDB bool, modules []Module, o *options) (*Container, error) {
+	if err := o.config.CORS.Validate(); err != nil {
+		return nil, err
+	}
 
-	// Initialize services
-	userSvc := userService.NewUserService(userRepository)
-	postSvc := postService.NewPostService(postRepository)
+	// Supplied first so it is stopped last
+	factory := NewFactory()
+	if ownsDB {
+		if err := factory.Supply(db); err != nil {
+			return nil, err
+		}
+	}
+	if err := factory.Supply(db.DB); err != nil {
+		return nil, err
+	}
+	// Components reading from the replica take the "replica" binding, see WithParamNames
+	if replica != nil {
+		if err := factory.Supply(replica, Named("replica")); err != nil {
+			return nil, err
+		}
+		if err := factory.Supply(replica.DB, Named("replica")); err != nil {
+			return nil, err
+		}
+	}
+	// Handlers issuing tokens take them as a dependency
+	tokens, err := newTokens(o.config.Auth)
+	if err != nil {
+		return nil, err
+	}
+	if err := factory.Supply(tokens); err != nil {
+		return nil, err
+	}
+	// Outside a request scope components see an empty request
+	if err := factory.Supply(&RequestContext{}); err != nil {
+		return nil, err
+	}
 
-	// Initialize handlers
-	userHdl := userHandler.NewUserHandler(userSvc)
-	postHdl := handler.NewPostHandler(postSvc)
+	// Repositories are request-scoped, so the ones built for a request use
+	// its transaction; services and handlers are singletons reaching them
+	// through FromScope
+	for _, module := range modules {
+		for _, constructor := range module.Providers() {
+			if err := factory.Provide(constructor, moduleLifetime(constructor)...); err != nil {
+				return nil, fmt.Errorf("module %s: %w", module.Name(), err)
+			}
+		}
+		log.Printf("🧩 Module enabled: %s (%s)", module.Name(), module.BasePath())
+	}
+	for _, override := range o.overrides {
+		if err := factory.Override(override.typ, override.value); err != nil {
+			return nil, err
+		}
+		log.Printf("🔁 Overriding %s with %T", override.typ, override.value)
+	}
 
-	// Initialize auto router
+	// Initialize auto router; v2 inherits every v1 route it doesn't override
 	autoRouter := NewAutoRouter()
+	if err := autoRouter.AddVersion(APIVersion{Name: "v2", Base: defaultVersion}); err != nil {
+		return nil, err
+	}
+	autoRouter.SetCORS(o.config.CORS)
+	autoRouter.SetFactory(factory)
 
 	container := &Container{
-		DB:          db.DB,
-		UserRepo:    userRepository,
-		PostRepo:    postRepository,
-		UserService: userSvc,
-		PostService: postSvc,
-		UserHandler: userHdl,
-		PostHandler: postHdl,
-		AutoRouter:  autoRouter,
+		DB:         db.DB,
+		Config:     o.config,
+		Tokens:     tokens,
+		Modules:    modules,
+		AutoRouter: autoRouter,
+		Factory:    factory,
+		Registry:   NewServiceRegistry(),
 	}
 
 	// 🚀 자동으로 모든 핸들러 라우트 등록
-	container.registerAllHandlers()
+	if err := container.registerAllHandlers(); err != nil {
+		return nil, err
+	}
+
+	if err := container.registerComponents(); err != nil {
+		return nil, err
+	}
+
+	// Reject conflicting routes before gin sees them
+	if err := autoRouter.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid route table: %w", err)
+	}
 
 	return container, nil
 }
 
-// registerAllHandlers automatically registers all handler routes
-func (c *Container) registerAllHandlers() {
-	log.Println("🔄 Starting automatic route registration...")
+// moduleLifetime registers repositories request-scoped, so they see the
+// request's *gorm.DB, and every other module component as a singleton
+func moduleLifetime(constructor interface{}) []ProvideOption {
+	if t := reflect.TypeOf(constructor); t != nil && t.Kind() == reflect.Func &&
+		t.NumOut() > 0 && typeKind(t.Out(0)) == NodeRepository {
+		return []ProvideOption{AsScoped()}
+	}
+	return nil
+}
 
-	// Register User domain routes
-	c.AutoRouter.RegisterHandler("/users", c.UserHandler)
+// registerAllHandlers resolves and routes every handler the modules mount
+func (c *Container) registerAllHandlers() error {
+	log.Println("🔄 Starting automatic route registration...")
 
-	// Register Post domain routes
-	c.AutoRouter.RegisterHandler("/posts", c.PostHandler)
+	for _, module := range c.Modules {
+		for _, mount := range module.Handlers() {
+			handler, err := c.Factory.Get(mount.Type)
+			if err != nil {
+				return fmt.Errorf("module %s: %w", module.Name(), err)
+			}
+			if err := c.AutoRouter.RegisterHandler(module.BasePath(), handler, mount.Options...); err != nil {
+				return fmt.Errorf("module %s: %w", module.Name(), err)
+			}
+		}
+	}
 
 	log.Println("✅ Automatic route registration completed!")
+	return nil
+}
+
+// registerComponents builds every module provider, including ones nothing
+// depends on such as RouteRegistrars, and registers all built components
+func (c *Container) registerComponents() error {
+	for _, module := range c.Modules {
+		for _, constructor := range module.Providers() {
+			if _, err := c.Factory.Get(reflect.TypeOf(constructor).Out(0)); err != nil {
+				return fmt.Errorf("module %s: %w", module.Name(), err)
+			}
+		}
+	}
+	// Named bindings are registered as e.g. "Database (replica)"
+	for _, instance := range c.Factory.cachedInOrder() {
+		name := serviceName(instance.value)
+		if instance.key.name != "" {
+			name = fmt.Sprintf("%s (%s)", name, instance.key.name)
+		}
+		c.Registry.Register(name, instance.value)
+	}
+	return nil
 }
 
 // RegisterRoutes registers all domain routes automatically
 func (c *Container) RegisterRoutes(router *gin.Engine) {
-	// API version grouping
-	v1 := router.Group("/api/v1")
+	// API version grouping: /api/v1, /api/v2, ...; every API request is
+	// authenticated if it carries a token and gets its own scope
+	api := router.Group(apiPrefix, middleware.Authenticate(c.Tokens), RequestScope(c.Factory, c.DB))
 
 	// 🚀 자동으로 모든 라우트 등록
-	c.AutoRouter.RegisterRoutes(v1)
+	c.AutoRouter.RegisterRoutes(api)
+	// Routes of RouteRegistrar components, next to the conventional ones
+	c.Registry.SetupAllRoutes(api, c)
+
+	// API documentation generated from the route table
+	c.registerDocsRoutes(router)
+	c.registerHealthRoutes(router)
 
 	log.Printf("📡 Total registered routes: %d", len(c.AutoRouter.GetRoutes()))
 }
 
+// RegisterAdminRoutes registers the internal endpoints, such as the route
+// catalog, dependency graph and metrics, which describe the whole application.
+// Serve router on a listener that isn't reachable publicly, see config.Config.AdminAddr.
+func (c *Container) RegisterAdminRoutes(router *gin.Engine) {
+	c.registerInternalRoutes(router)
+	c.registerGraphRoute(router)
+	c.registerMetricsRoute(router)
+}
+
+// newTokens creates the token issuer from cfg, falling back to a random key
+// for development setups without JWT_SECRET
+func newTokens(cfg config.AuthConfig) (*auth.Tokens, error) {
+	secret := []byte(cfg.JWTSecret)
+	if len(secret) == 0 {
+		log.Println("⚠️ JWT_SECRET is not set; tokens are signed with a random key and won't survive a restart")
+		secret = auth.RandomSecret()
+	}
+	expiry := cfg.JWTExpiry
+	if expiry == 0 {
+		expiry = 24 * time.Hour
+	}
+	return auth.NewTokens(secret, expiry)
+}
+
 // GetRegisteredRoutes returns all registered routes for debugging
 func (c *Container) GetRegisteredRoutes() []RouteInfo {
 	return c.AutoRouter.GetRoutes()
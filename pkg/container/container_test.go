@@ -0,0 +1,35 @@
+package container
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"study-go-controller/pkg/config"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestInternalEndpointsOnlyServeOnTheAdminRouter(t *testing.T) {
+	c, err := NewRouteContainer(WithConfig(&config.Config{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	public := gin.New()
+	c.RegisterRoutes(public)
+	admin := gin.New()
+	c.RegisterAdminRoutes(admin)
+
+	for _, path := range []string{"/_internal/routes", "/_internal/graph", "/metrics"} {
+		recorder := httptest.NewRecorder()
+		public.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusNotFound {
+			t.Errorf("public GET %s = %d, want 404", path, recorder.Code)
+		}
+
+		recorder = httptest.NewRecorder()
+		admin.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("admin GET %s = %d, want 200", path, recorder.Code)
+		}
+	}
+}
@@ -3,105 +3,482 @@ package container
 import (
 	"fmt"
 	"reflect"
-	"study-go-controller/internal/domain/post/handler"
-	postRepo "study-go-controller/internal/domain/post/repository"
-	postService "study-go-controller/internal/domain/post/service"
-	userHandler "study-go-controller/internal/domain/user/handler"
-	userRepo "study-go-controller/internal/domain/user/repository"
-	userService "study-go-controller/internal/domain/user/service"
-	"study-go-controller/pkg/database"
+	"sort"
+	"strings"
+	"sync"
 )
 
-// Factory creates instances with automatic dependency injection
+// Lifetime controls how often a provider's constructor runs
+type Lifetime int
+
+const (
+	// Singleton builds one instance per Factory; this is the default
+	Singleton Lifetime = iota
+	// Transient builds a new instance every time the type is resolved
+	Transient
+	// Scoped builds one instance per scope (see Factory.Scope); the root
+	// factory acts as a scope of its own
+	Scoped
+)
+
+// String returns the lifetime's name
+func (l Lifetime) String() string {
+	switch l {
+	case Transient:
+		return "transient"
+	case Scoped:
+		return "scoped"
+	default:
+		return "singleton"
+	}
+}
+
+// ProvideOption configures a provider
+type ProvideOption func(*provider)
+
+// AsTransient makes a provider build a new instance on every resolution
+func AsTransient() ProvideOption {
+	return func(p *provider) { p.lifetime = Transient }
+}
+
+// AsScoped makes a provider build one instance per scope, so it sees values
+// supplied to the scope such as a request's transaction
+func AsScoped() ProvideOption {
+	return func(p *provider) { p.lifetime = Scoped }
+}
+
+// Named registers the provider under a name, so several providers of one
+// type can coexist, e.g. a "replica" *gorm.DB next to the unnamed primary
+func Named(name string) ProvideOption {
+	return func(p *provider) { p.out.name = name }
+}
+
+// WithParamNames resolves the constructor's parameters by name, in order;
+// "" keeps the unnamed binding:
+//
+//	factory.Provide(report.NewRepository, container.WithParamNames("replica"))
+func WithParamNames(names ...string) ProvideOption {
+	return func(p *provider) {
+		for i := 0; i < len(names) && i < len(p.params); i++ {
+			p.params[i].name = names[i]
+		}
+	}
+}
+
+// key identifies a binding: a type and an optional name
+type key struct {
+	t    reflect.Type
+	name string
+}
+
+// String renders the key, e.g. "*gorm.DB" or "*gorm.DB (replica)"
+func (k key) String() string {
+	if k.name == "" {
+		return k.t.String()
+	}
+	return fmt.Sprintf("%s (%s)", k.t, k.name)
+}
+
+// provider is a registered constructor or supplied value
+type provider struct {
+	constructor reflect.Value // invalid for supplied values
+	out         key
+	params      []key
+	returnsErr  bool
+	lifetime    Lifetime
+}
+
+// build is an instance under construction; goroutines resolving the same
+// key wait for it instead of building a duplicate
+type build struct {
+	done  chan struct{}
+	value reflect.Value
+	err   error
+}
+
+// Factory creates instances with automatic dependency injection. Constructors
+// are registered with Provide and resolved by type: each parameter is built
+// by the provider of that type, recursively.
+//
+// A Factory is safe for concurrent use. Singletons are built lazily and
+// exactly once: concurrent resolutions of a key wait for the first one. No
+// lock is held while constructors run, so unrelated ones don't block each other.
 type Factory struct {
-	instances map[reflect.Type]interface{}
-	database  *database.Database
+	mu        sync.Mutex
+	parent    *Factory          // nil for the root factory
+	providers map[key]*provider // shared with scopes, guarded by the root's mu
+	instances map[key]reflect.Value
+	building  map[key]*build
+	order     []key        // cached instances in construction order
+	acyclic   map[key]bool // keys whose providers were checked for cycles; root only
 }
 
-// NewFactory creates a new factory instance
-func NewFactory() (*Factory, error) {
-	db, err := database.NewDatabase()
-	if err != nil {
-		return nil, err
+// NewFactory creates an empty factory
+func NewFactory() *Factory {
+	return &Factory{
+		providers: make(map[key]*provider),
+		instances: make(map[key]reflect.Value),
+		building:  make(map[key]*build),
+		acyclic:   make(map[key]bool),
+	}
+}
+
+// Provide registers a constructor: a function returning T or (T, error).
+// T is the declared result type, so a constructor returning an interface
+// satisfies parameters of that interface, e.g.
+//
+//	factory.Provide(repository.NewUserRepository) // func(*gorm.DB) UserRepository
+//	factory.Provide(service.NewUserService)       // func(UserRepository) UserService
+func (f *Factory) Provide(constructor interface{}, opts ...ProvideOption) error {
+	fn := reflect.ValueOf(constructor)
+	fnType := fn.Type()
+	if fnType.Kind() != reflect.Func {
+		return fmt.Errorf("provider must be a constructor function, got %s", fnType)
+	}
+	if fnType.IsVariadic() {
+		return fmt.Errorf("provider %s must not be variadic", fnType)
 	}
 
-	if err := db.AutoMigrate(); err != nil {
-		return nil, err
+	p := &provider{constructor: fn, lifetime: Singleton}
+	switch {
+	case fnType.NumOut() == 1 && fnType.Out(0) != errorType:
+	case fnType.NumOut() == 2 && fnType.Out(1) == errorType:
+		p.returnsErr = true
+	default:
+		return fmt.Errorf("provider %s must return T or (T, error)", fnType)
+	}
+	p.out = key{t: fnType.Out(0)}
+	for i := 0; i < fnType.NumIn(); i++ {
+		p.params = append(p.params, key{t: fnType.In(i)})
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	root := f.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	return root.register(p)
+}
+
+// Supply registers an already built value under its type, e.g. a *gorm.DB;
+// add Named to supply one of several values of a type. In a scope it
+// overrides the root's value for everything the scope builds.
+func (f *Factory) Supply(value interface{}, opts ...ProvideOption) error {
+	if value == nil {
+		return fmt.Errorf("cannot supply untyped nil")
+	}
+	v := reflect.ValueOf(value)
+	p := &provider{out: key{t: v.Type()}, lifetime: Singleton}
+	for _, opt := range opts {
+		opt(p)
 	}
 
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.parent == nil {
+		if err := f.register(p); err != nil {
+			return err
+		}
+	}
+	f.store(p.out, v)
+	return nil
+}
+
+// Override replaces the provider of t, if any, with value; everything
+// resolved afterwards that depends on t receives value
+func (f *Factory) Override(t reflect.Type, value interface{}) error {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || !v.Type().AssignableTo(t) {
+		return fmt.Errorf("override for %s must be assignable to it, got %T", t, value)
+	}
+	converted := reflect.New(t).Elem()
+	converted.Set(v)
+
+	k := key{t: t}
+	root := f.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	root.providers[k] = &provider{out: k, lifetime: Singleton}
+	if _, cached := root.instances[k]; cached {
+		// Overriding again keeps the instance's place in the construction order
+		root.instances[k] = converted
+		return nil
+	}
+	root.store(k, converted)
+	return nil
+}
+
+// Scope returns a child factory sharing this factory's providers. Singletons
+// still come from the root; scoped providers build their own instance in
+// the child, from the child's supplied values.
+func (f *Factory) Scope() *Factory {
 	return &Factory{
-		instances: make(map[reflect.Type]interface{}),
-		database:  db,
-	}, nil
+		parent:    f,
+		providers: f.providers,
+		instances: make(map[key]reflect.Value),
+		building:  make(map[key]*build),
+	}
+}
+
+// Lifetime returns the lifetime of the unnamed provider for t
+func (f *Factory) Lifetime(t reflect.Type) (Lifetime, bool) {
+	p, ok := f.lookup(key{t: t})
+	if !ok {
+		return 0, false
+	}
+	return p.lifetime, true
+}
+
+// register adds a provider, rejecting a second one for the same key;
+// the caller holds the root's mu
+func (f *Factory) register(p *provider) error {
+	if _, exists := f.providers[p.out]; exists {
+		return fmt.Errorf("provider for %s already registered", p.out)
+	}
+	f.providers[p.out] = p
+	// The new provider may close a cycle through keys already checked
+	f.acyclic = make(map[key]bool)
+	return nil
 }
 
 // Get retrieves or creates an instance of the specified type
 func (f *Factory) Get(serviceType reflect.Type) (interface{}, error) {
-	// Check if instance already exists
-	if instance, exists := f.instances[serviceType]; exists {
+	return f.GetNamed(serviceType, "")
+}
+
+// GetNamed retrieves or creates the instance of a type registered under name
+func (f *Factory) GetNamed(serviceType reflect.Type, name string) (interface{}, error) {
+	instance, err := f.get(key{t: serviceType, name: name})
+	if err != nil {
+		return nil, err
+	}
+	return instance.Interface(), nil
+}
+
+// Populate resolves each target's element type and stores the instance in it:
+//
+//	var userService service.UserService
+//	err := factory.Populate(&userService)
+func (f *Factory) Populate(targets ...interface{}) error {
+	for _, target := range targets {
+		ptr := reflect.ValueOf(target)
+		if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+			return fmt.Errorf("populate target must be a non-nil pointer, got %T", target)
+		}
+		instance, err := f.get(key{t: ptr.Type().Elem()})
+		if err != nil {
+			return err
+		}
+		ptr.Elem().Set(instance)
+	}
+	return nil
+}
+
+// get checks k's providers for cycles before resolving it, so concurrent
+// resolutions can never end up waiting on each other around a cycle
+func (f *Factory) get(k key) (reflect.Value, error) {
+	if err := f.root().checkAcyclic(k); err != nil {
+		return reflect.Value{}, err
+	}
+	return f.resolve(k, nil)
+}
+
+// resolve builds k, with path holding the keys currently being built
+func (f *Factory) resolve(k key, path []key) (reflect.Value, error) {
+	for i, building := range path {
+		if building == k {
+			return reflect.Value{}, fmt.Errorf("dependency cycle: %s", formatPath(append(path[i:], k)))
+		}
+	}
+	if instance, ok := f.cached(k); ok {
 		return instance, nil
 	}
 
-	// Create new instance based on type
-	var instance interface{}
-	var err error
+	p, ok := f.lookup(k)
+	if !ok {
+		if len(path) == 0 {
+			return reflect.Value{}, fmt.Errorf("no provider for %s", k)
+		}
+		return reflect.Value{}, fmt.Errorf("no provider for %s (required by %s)", k, formatPath(path))
+	}
+	if p.lifetime == Singleton && f.parent != nil {
+		return f.root().resolve(k, path)
+	}
+
+	// Full slice expression so sibling parameters don't share the appended path
+	path = append(path[:len(path):len(path)], k)
+	if p.lifetime == Transient {
+		return f.construct(p, path)
+	}
+
+	f.mu.Lock()
+	if instance, ok := f.instances[k]; ok {
+		f.mu.Unlock()
+		return instance, nil
+	}
+	if pending, ok := f.building[k]; ok {
+		f.mu.Unlock()
+		<-pending.done
+		return pending.value, pending.err
+	}
+	b := &build{done: make(chan struct{})}
+	f.building[k] = b
+	f.mu.Unlock()
 
-	switch serviceType.String() {
-	case "repository.UserRepository":
-		instance = userRepo.NewUserRepository(f.database.DB)
-	case "repository.PostRepository":
-		instance = postRepo.NewPostRepository(f.database.DB)
-	case "service.UserService":
-		userRepoInstance, err := f.Get(reflect.TypeOf((*userRepo.UserRepository)(nil)).Elem())
+	b.value, b.err = f.construct(p, path)
+
+	// Failures aren't cached, so a later resolution can try again
+	f.mu.Lock()
+	delete(f.building, k)
+	if b.err == nil {
+		f.store(k, b.value)
+	}
+	f.mu.Unlock()
+	close(b.done)
+	return b.value, b.err
+}
+
+// construct resolves p's parameters and calls its constructor, reporting
+// errors and panics with the path that led to it
+func (f *Factory) construct(p *provider, path []key) (instance reflect.Value, err error) {
+	args := make([]reflect.Value, len(p.params))
+	for i, param := range p.params {
+		arg, err := f.resolve(param, path)
 		if err != nil {
-			return nil, err
+			return reflect.Value{}, err
 		}
-		instance = userService.NewUserService(userRepoInstance.(userRepo.UserRepository))
-	case "service.PostService":
-		postRepoInstance, err := f.Get(reflect.TypeOf((*postRepo.PostRepository)(nil)).Elem())
-		if err != nil {
-			return nil, err
+		args[i] = arg
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("building %s: panic: %v", formatPath(path), r)
 		}
-		instance = postService.NewPostService(postRepoInstance.(postRepo.PostRepository))
-	case "*handler.UserHandler":
-		userSvcInstance, err := f.Get(reflect.TypeOf((*userService.UserService)(nil)).Elem())
-		if err != nil {
-			return nil, err
+	}()
+	results := p.constructor.Call(args)
+	if p.returnsErr && !results[1].IsNil() {
+		return reflect.Value{}, fmt.Errorf("building %s: %w", formatPath(path), results[1].Interface().(error))
+	}
+	return results[0], nil
+}
+
+// checkAcyclic walks the providers reachable from k and reports the first
+// cycle it finds; checked keys are remembered until the next Provide
+func (f *Factory) checkAcyclic(k key) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var path []key
+	var walk func(k key) error
+	walk = func(k key) error {
+		if f.acyclic[k] {
+			return nil
 		}
-		instance = userHandler.NewUserHandler(userSvcInstance.(userService.UserService))
-	case "*handler.PostHandler":
-		postSvcInstance, err := f.Get(reflect.TypeOf((*postService.PostService)(nil)).Elem())
-		if err != nil {
-			return nil, err
+		for i, seen := range path {
+			if seen == k {
+				return fmt.Errorf("dependency cycle: %s", formatPath(append(path[i:], k)))
+			}
 		}
-		instance = handler.NewPostHandler(postSvcInstance.(postService.PostService))
-	default:
-		return nil, fmt.Errorf("unknown service type: %s", serviceType.String())
+		p, ok := f.providers[k]
+		if !ok {
+			// Missing providers are reported when resolution reaches them
+			return nil
+		}
+
+		path = append(path, k)
+		for _, param := range p.params {
+			if err := walk(param); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		f.acyclic[k] = true
+		return nil
 	}
+	return walk(k)
+}
 
-	if err != nil {
-		return nil, err
+// cached returns the instance this factory holds for k
+func (f *Factory) cached(k key) (reflect.Value, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	instance, ok := f.instances[k]
+	return instance, ok
+}
+
+// lookup returns the provider for k
+func (f *Factory) lookup(k key) (*provider, bool) {
+	root := f.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	p, ok := root.providers[k]
+	return p, ok
+}
+
+// root returns the factory at the top of the scope chain
+func (f *Factory) root() *Factory {
+	for f.parent != nil {
+		f = f.parent
+	}
+	return f
+}
+
+// sortedProviders returns every provider ordered by key, for stable output
+func (f *Factory) sortedProviders() []*provider {
+	root := f.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	providers := make([]*provider, 0, len(root.providers))
+	for _, p := range root.providers {
+		providers = append(providers, p)
 	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].out.String() < providers[j].out.String() })
+	return providers
+}
 
-	// Store instance for reuse
-	f.instances[serviceType] = instance
-	return instance, nil
+// store caches an instance and records when it was built; the caller holds mu
+func (f *Factory) store(k key, instance reflect.Value) {
+	f.instances[k] = instance
+	f.order = append(f.order, k)
 }
 
-// GetUserHandler convenience method for getting user handler
-func (f *Factory) GetUserHandler() (*userHandler.UserHandler, error) {
-	instance, err := f.Get(reflect.TypeOf((*userHandler.UserHandler)(nil)))
-	if err != nil {
-		return nil, err
+// Singletons returns the instances cached so far in construction order,
+// which puts every instance after its dependencies. For the root factory
+// that includes scoped providers resolved outside any request. Transient
+// instances aren't tracked; whoever resolved them owns them.
+func (f *Factory) Singletons() []interface{} {
+	cached := f.cachedInOrder()
+	singletons := make([]interface{}, len(cached))
+	for i, instance := range cached {
+		singletons[i] = instance.value
 	}
-	return instance.(*userHandler.UserHandler), nil
+	return singletons
 }
 
-// GetPostHandler convenience method for getting post handler
-func (f *Factory) GetPostHandler() (*handler.PostHandler, error) {
-	instance, err := f.Get(reflect.TypeOf((*handler.PostHandler)(nil)))
-	if err != nil {
-		return nil, err
+// cachedInstance is a cached instance and the key it was resolved under
+type cachedInstance struct {
+	key   key
+	value interface{}
+}
+
+// cachedInOrder returns the cached instances in construction order, see Singletons
+func (f *Factory) cachedInOrder() []cachedInstance {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cached := make([]cachedInstance, len(f.order))
+	for i, k := range f.order {
+		cached[i] = cachedInstance{key: k, value: f.instances[k].Interface()}
+	}
+	return cached
+}
+
+// formatPath renders a resolution path, e.g. "*handler.UserHandler -> service.UserService"
+func formatPath(path []key) string {
+	names := make([]string, len(path))
+	for i, k := range path {
+		names[i] = k.String()
 	}
-	return instance.(*handler.PostHandler), nil
+	return strings.Join(names, " -> ")
 }
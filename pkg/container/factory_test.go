@@ -0,0 +1,408 @@
+package container
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Fixtures: a store built from a supplied DSN, and a service using the store
+type (
+	testDSN   string
+	testStore struct{ dsn testDSN }
+	testUsers struct{ store *testStore }
+)
+
+// counted wraps the store constructor, counting its calls
+func counted(calls *int) func(testDSN) *testStore {
+	return func(dsn testDSN) *testStore {
+		*calls++
+		return &testStore{dsn: dsn}
+	}
+}
+
+// mustGet resolves T from f
+func mustGet[T any](t *testing.T, f *Factory) T {
+	t.Helper()
+	instance, err := f.Get(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return instance.(T)
+}
+
+// newStoreFactory supplies the root DSN and provides the store with opts
+func newStoreFactory(t *testing.T, calls *int, opts ...ProvideOption) *Factory {
+	t.Helper()
+	f := NewFactory()
+	if err := f.Supply(testDSN("root")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Provide(counted(calls), opts...); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Provide(func(store *testStore) *testUsers { return &testUsers{store: store} }); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestSingletonsAreBuiltOncePerFactory(t *testing.T) {
+	var calls int
+	f := newStoreFactory(t, &calls)
+
+	first := mustGet[*testStore](t, f)
+	scope := f.Scope()
+	if err := scope.Supply(testDSN("request")); err != nil {
+		t.Fatal(err)
+	}
+	if mustGet[*testStore](t, f) != first || mustGet[*testStore](t, scope) != first || calls != 1 {
+		t.Fatalf("singleton was built %d times", calls)
+	}
+	// Scopes don't change what singletons were built from
+	if first.dsn != "root" {
+		t.Errorf("singleton dsn = %s, want root", first.dsn)
+	}
+	if lifetime, _ := f.Lifetime(reflect.TypeOf(first)); lifetime != Singleton {
+		t.Errorf("Lifetime = %s, want singleton", lifetime)
+	}
+}
+
+func TestTransientsAreBuiltOnEveryResolution(t *testing.T) {
+	var calls int
+	f := newStoreFactory(t, &calls, AsTransient())
+
+	first, second := mustGet[*testStore](t, f), mustGet[*testStore](t, f)
+	if first == second || calls != 2 {
+		t.Fatalf("transient resolved twice = same instance %v, %d calls", first == second, calls)
+	}
+	// The singleton depending on it got its own instance
+	if users := mustGet[*testUsers](t, f); users.store == first || users.store == second || calls != 3 {
+		t.Errorf("singleton shares a transient instance, %d calls", calls)
+	}
+	for _, singleton := range f.Singletons() {
+		if _, ok := singleton.(*testStore); ok {
+			t.Error("transient instance is tracked as a singleton")
+		}
+	}
+}
+
+func TestScopedInstancesArePerScope(t *testing.T) {
+	var calls int
+	f := newStoreFactory(t, &calls, AsScoped())
+
+	newScope := func(dsn testDSN) *Factory {
+		scope := f.Scope()
+		if err := scope.Supply(dsn); err != nil {
+			t.Fatal(err)
+		}
+		return scope
+	}
+	one, two := newScope("one"), newScope("two")
+
+	inOne := mustGet[*testStore](t, one)
+	if mustGet[*testStore](t, one) != inOne {
+		t.Error("scoped provider built twice in one scope")
+	}
+	inTwo := mustGet[*testStore](t, two)
+	if inTwo == inOne || inOne.dsn != "one" || inTwo.dsn != "two" {
+		t.Errorf("scopes got %q and %q, want their own values", inOne.dsn, inTwo.dsn)
+	}
+	// The root acts as a scope of its own
+	if inRoot := mustGet[*testStore](t, f); inRoot == inOne || inRoot.dsn != "root" || calls != 3 {
+		t.Errorf("root got %q after %d calls, want its own instance", inRoot.dsn, calls)
+	}
+	// Singletons are built in the root, so they never capture a scope's instance
+	if users := mustGet[*testUsers](t, one); users.store == inOne {
+		t.Error("singleton captured a scoped instance")
+	}
+}
+
+func TestProvideRejectsInvalidConstructors(t *testing.T) {
+	f := NewFactory()
+	tests := map[string]interface{}{
+		"not a function":   testStore{},
+		"variadic":         func(...testDSN) *testStore { return nil },
+		"no result":        func() {},
+		"error only":       func() error { return nil },
+		"second non-error": func() (*testStore, int) { return nil, 0 },
+	}
+	for name, constructor := range tests {
+		if err := f.Provide(constructor); err == nil {
+			t.Errorf("%s: Provide accepted %T", name, constructor)
+		}
+	}
+
+	if err := f.Provide(func() *testStore { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Provide(func() *testStore { return nil }); err == nil {
+		t.Error("Provide accepted a second provider for *testStore")
+	}
+	if err := f.Supply(nil); err == nil {
+		t.Error("Supply accepted untyped nil")
+	}
+}
+
+// Fixtures for cycles: cycleA needs cycleB, which may need cycleA
+type (
+	cycleA struct{}
+	cycleB struct{}
+)
+
+func TestCheckAcyclicRejectsCyclesBeforeBuilding(t *testing.T) {
+	var built bool
+	f := NewFactory()
+	if err := f.Provide(func(*cycleB) *cycleA { built = true; return &cycleA{} }); err != nil {
+		t.Fatal(err)
+	}
+
+	// Missing providers are left to resolution, which names the chain
+	_, err := f.Get(reflect.TypeOf(&cycleA{}))
+	if err == nil || !strings.Contains(err.Error(), "no provider for *container.cycleB (required by *container.cycleA)") {
+		t.Fatalf("Get with a missing provider = %v", err)
+	}
+	if !f.acyclic[key{t: reflect.TypeOf(&cycleA{})}] {
+		t.Error("checked key isn't remembered")
+	}
+
+	// Closing the cycle invalidates what was checked
+	if err := f.Provide(func(*cycleA) *cycleB { built = true; return &cycleB{} }); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.acyclic) != 0 {
+		t.Error("Provide kept the checked keys")
+	}
+	_, err = f.Get(reflect.TypeOf(&cycleA{}))
+	want := "dependency cycle: *container.cycleA -> *container.cycleB -> *container.cycleA"
+	if err == nil || err.Error() != want {
+		t.Fatalf("Get in a cycle = %v, want %s", err, want)
+	}
+	if built {
+		t.Error("a constructor in the cycle ran")
+	}
+}
+
+func TestCheckAcyclicAcceptsSharedDependencies(t *testing.T) {
+	// testUsers and the store both need the DSN: a diamond, not a cycle
+	f := NewFactory()
+	if err := f.Supply(testDSN("root")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Provide(func(dsn testDSN) *testStore { return &testStore{dsn: dsn} }); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Provide(func(store *testStore, _ testDSN) *testUsers { return &testUsers{store: store} }); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.root().checkAcyclic(key{t: reflect.TypeOf(&testUsers{})}); err != nil {
+		t.Fatal(err)
+	}
+	if users := mustGet[*testUsers](t, f); users.store.dsn != "root" {
+		t.Errorf("users built from %q", users.store.dsn)
+	}
+}
+
+func TestSelfDependencyIsACycle(t *testing.T) {
+	f := NewFactory()
+	if err := f.Provide(func(*cycleA) *cycleA { return &cycleA{} }); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.root().checkAcyclic(key{t: reflect.TypeOf(&cycleA{})}); err == nil ||
+		!strings.Contains(err.Error(), "dependency cycle") {
+		t.Fatalf("checkAcyclic = %v, want a cycle", err)
+	}
+}
+
+// resolveConcurrently resolves T from f in n goroutines released at once
+func resolveConcurrently[T any](f *Factory, n int) ([]T, []error) {
+	instances, errs := make([]T, n), make([]error, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			instance, err := f.Get(reflect.TypeOf((*T)(nil)).Elem())
+			if err == nil {
+				instances[i] = instance.(T)
+			}
+			errs[i] = err
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return instances, errs
+}
+
+// Run with -race: concurrent resolutions must share one build
+func TestConcurrentResolutionsBuildASingletonOnce(t *testing.T) {
+	var calls atomic.Int32
+	f := NewFactory()
+	err := f.Provide(func() *testStore {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond) // keep the build open while the others resolve
+		return &testStore{dsn: "root"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Provide(func(store *testStore) *testUsers { return &testUsers{store: store} }); err != nil {
+		t.Fatal(err)
+	}
+
+	users, errs := resolveConcurrently[*testUsers](f, 32)
+	for i := range users {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if users[i] != users[0] || users[i].store != users[0].store {
+			t.Fatal("concurrent resolutions got different instances")
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("constructor ran %d times, want once", calls.Load())
+	}
+	if got := len(f.Singletons()); got != 2 {
+		t.Errorf("%d singletons cached, want 2", got)
+	}
+}
+
+func TestConcurrentResolutionsShareTheBuildError(t *testing.T) {
+	errBoom := errors.New("boom")
+	var calls atomic.Int32
+	f := NewFactory()
+	err := f.Provide(func() (*testStore, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return nil, errBoom
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Provide(func(store *testStore) *testUsers { return &testUsers{store: store} }); err != nil {
+		t.Fatal(err)
+	}
+
+	// Waiters get the error of the build they waited for, late arrivals build again
+	const n = 16
+	_, errs := resolveConcurrently[*testUsers](f, n)
+	for _, err := range errs {
+		if !errors.Is(err, errBoom) || !strings.Contains(err.Error(), "building *container.testUsers -> *container.testStore") {
+			t.Fatalf("Get = %v, want the constructor's error with its path", err)
+		}
+	}
+	if got := calls.Load(); got < 1 || got > n {
+		t.Errorf("constructor ran %d times", got)
+	}
+
+	// Failures aren't cached
+	before := calls.Load()
+	if _, err := f.Get(reflect.TypeOf(&testStore{})); !errors.Is(err, errBoom) || calls.Load() != before+1 {
+		t.Errorf("Get after a failure = %v after %d calls, want a new attempt", err, calls.Load()-before)
+	}
+	if len(f.Singletons()) != 0 {
+		t.Error("a failed build was cached")
+	}
+}
+
+func TestNamedBindings(t *testing.T) {
+	f := NewFactory()
+	if err := f.Supply(testDSN("primary")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Supply(testDSN("replica"), Named("replica")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Provide(func(dsn testDSN) *testStore { return &testStore{dsn: dsn} }); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Provide(func(dsn testDSN) *testStore { return &testStore{dsn: dsn} },
+		Named("replica"), WithParamNames("replica")); err != nil {
+		t.Fatal(err)
+	}
+
+	storeType := reflect.TypeOf(&testStore{})
+	primary, err := f.Get(storeType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replica, err := f.GetNamed(storeType, "replica")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if primary.(*testStore).dsn != "primary" || replica.(*testStore).dsn != "replica" {
+		t.Errorf("stores built from %q and %q, want primary and replica", primary.(*testStore).dsn, replica.(*testStore).dsn)
+	}
+
+	_, err = f.GetNamed(storeType, "archive")
+	if want := "no provider for *container.testStore (archive)"; err == nil || err.Error() != want {
+		t.Errorf("GetNamed with an unknown name = %v, want %s", err, want)
+	}
+	if err := f.Supply(testDSN("again"), Named("replica")); err == nil {
+		t.Error("Supply accepted a second value under one name")
+	}
+}
+
+func TestResolutionErrors(t *testing.T) {
+	f := NewFactory()
+	if err := f.Provide(func(*cycleB) *cycleA { return &cycleA{} }); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		from  *Factory
+		typ   reflect.Type
+		want  string
+		setup func()
+	}{
+		{"unknown type", f, reflect.TypeOf(&testUsers{}), "no provider for *container.testUsers", nil},
+		{"missing dependency", f, reflect.TypeOf(&cycleA{}),
+			"no provider for *container.cycleB (required by *container.cycleA)", nil},
+		{"cycle", f, reflect.TypeOf(&cycleB{}),
+			"dependency cycle: *container.cycleB -> *container.cycleA -> *container.cycleB", func() {
+				if err := f.Provide(func(*cycleA) *cycleB { return &cycleB{} }); err != nil {
+					t.Fatal(err)
+				}
+			}},
+		{"cycle from a scope", f.Scope(), reflect.TypeOf(&cycleA{}),
+			"dependency cycle: *container.cycleA -> *container.cycleB -> *container.cycleA", nil},
+	}
+	for _, tt := range tests {
+		if tt.setup != nil {
+			tt.setup()
+		}
+		if _, err := tt.from.Get(tt.typ); err == nil || err.Error() != tt.want {
+			t.Errorf("%s: Get = %v, want %s", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestOverrideKeepsTheConstructionOrder(t *testing.T) {
+	var calls int
+	f := newStoreFactory(t, &calls)
+	mustGet[*testUsers](t, f)
+	before := len(f.Singletons())
+
+	storeType := reflect.TypeOf(&testStore{})
+	for _, dsn := range []testDSN{"first", "second"} {
+		if err := f.Override(storeType, &testStore{dsn: dsn}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(f.Singletons()); got != before {
+		t.Errorf("%d singletons after overriding twice, want %d", got, before)
+	}
+	if store := mustGet[*testStore](t, f); store.dsn != "second" {
+		t.Errorf("resolved store %q, want the last override", store.dsn)
+	}
+	if err := f.Override(storeType, testStore{}); err == nil {
+		t.Error("Override accepted a value of another type")
+	}
+}
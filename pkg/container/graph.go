@@ -0,0 +1,336 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Node kinds of the dependency graph
+const (
+	NodeRoute      = "route"
+	NodeHandler    = "handler"
+	NodeService    = "service"
+	NodeRepository = "repository"
+	NodeValue      = "value"     // supplied with Factory.Supply, e.g. *gorm.DB
+	NodeComponent  = "component" // provided, but outside the handler/service/repository layers
+	NodeMissing    = "missing"   // required without a provider
+)
+
+// Issue kinds reported by DependencyGraph
+const (
+	IssueUnusedProvider  = "unused_provider"
+	IssueMissingProvider = "missing_provider"
+	IssueLayering        = "layering_violation"
+	IssueCycle           = "cycle"
+)
+
+// GraphNode is a component or route of the dependency graph
+type GraphNode struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	Domain   string `json:"domain,omitempty"`
+	Lifetime string `json:"lifetime,omitempty"`
+}
+
+// GraphEdge points from a node to something it depends on
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// GraphIssue is a problem found in the graph
+type GraphIssue struct {
+	Kind    string   `json:"kind"`
+	Message string   `json:"message"`
+	Nodes   []string `json:"nodes"`
+}
+
+// DependencyGraph is the container's providers and routes, and what they depend on
+type DependencyGraph struct {
+	Nodes  []GraphNode  `json:"nodes"`
+	Edges  []GraphEdge  `json:"edges"`
+	Issues []GraphIssue `json:"issues"`
+}
+
+// DependencyGraph builds the graph from the factory's providers and the
+// route table, and validates it
+func (c *Container) DependencyGraph() *DependencyGraph {
+	return BuildDependencyGraph(c.Factory, c.AutoRouter.GetRoutes(), apiPrefix)
+}
+
+// BuildDependencyGraph builds the graph of factory's providers and the
+// routes served under prefix, then reports unused and missing providers,
+// layering violations and cycles
+func BuildDependencyGraph(factory *Factory, routes []RouteInfo, prefix string) *DependencyGraph {
+	g := &DependencyGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}, Issues: []GraphIssue{}}
+
+	providers := factory.sortedProviders()
+	provided := make(map[key]bool, len(providers))
+	for _, p := range providers {
+		provided[p.out] = true
+	}
+
+	nodes := make(map[string]GraphNode)
+	deps := make(map[string][]string)
+	used := make(map[string]bool)
+	var missing []string
+	for _, p := range providers {
+		node := GraphNode{ID: p.out.String(), Kind: typeKind(p.out.t), Domain: typeDomain(p.out.t), Lifetime: p.lifetime.String()}
+		if !p.constructor.IsValid() {
+			node.Kind = NodeValue
+		}
+		nodes[node.ID] = node
+		// Registrars and health checks are used by the container itself
+		if p.out.t.Implements(routeRegistrarType) || p.out.t.Implements(healthCheckerType) ||
+			p.out.t.Implements(readinessCheckerType) {
+			used[node.ID] = true
+		}
+
+		for _, param := range p.params {
+			if !provided[param] && nodes[param.String()].ID == "" {
+				nodes[param.String()] = GraphNode{ID: param.String(), Kind: NodeMissing}
+				missing = append(missing, param.String())
+				g.Issues = append(g.Issues, GraphIssue{
+					Kind:    IssueMissingProvider,
+					Message: fmt.Sprintf("no provider for %s (required by %s)", param, p.out),
+					Nodes:   []string{param.String(), p.out.String()},
+				})
+			}
+			deps[node.ID] = append(deps[node.ID], param.String())
+			used[param.String()] = true
+		}
+	}
+
+	// Routes are the entry points; a handler is used when something routes to it
+	var routeIDs []string
+	for _, route := range routes {
+		if route.handlerType == nil {
+			continue
+		}
+		id := route.Method + " " + versionedPath(prefix, route)
+		nodes[id] = GraphNode{ID: id, Kind: NodeRoute, Domain: typeDomain(route.handlerType)}
+		deps[id] = append(deps[id], route.handlerType.String())
+		used[route.handlerType.String()] = true
+		routeIDs = append(routeIDs, id)
+	}
+
+	for _, p := range providers {
+		g.Nodes = append(g.Nodes, nodes[p.out.String()])
+	}
+	for _, id := range append(missing, routeIDs...) {
+		g.Nodes = append(g.Nodes, nodes[id])
+	}
+	for _, node := range g.Nodes {
+		for _, dep := range deps[node.ID] {
+			g.Edges = append(g.Edges, GraphEdge{From: node.ID, To: dep})
+		}
+	}
+
+	for _, node := range g.Nodes {
+		if node.Kind != NodeRoute && node.Kind != NodeValue && node.Kind != NodeMissing && !used[node.ID] {
+			g.Issues = append(g.Issues, GraphIssue{
+				Kind:    IssueUnusedProvider,
+				Message: fmt.Sprintf("%s is provided but nothing depends on it", node.ID),
+				Nodes:   []string{node.ID},
+			})
+		}
+	}
+	for _, edge := range g.Edges {
+		if message := layeringViolation(nodes[edge.From], nodes[edge.To]); message != "" {
+			g.Issues = append(g.Issues, GraphIssue{
+				Kind:    IssueLayering,
+				Message: message,
+				Nodes:   []string{edge.From, edge.To},
+			})
+		}
+	}
+	for _, cycle := range findCycles(g.Nodes, deps) {
+		g.Issues = append(g.Issues, GraphIssue{
+			Kind:    IssueCycle,
+			Message: "dependency cycle: " + strings.Join(cycle, " -> "),
+			Nodes:   cycle,
+		})
+	}
+	return g
+}
+
+// layeringViolation describes an edge that skips or crosses layers, or returns ""
+func layeringViolation(from, to GraphNode) string {
+	switch {
+	case from.Kind == NodeHandler && to.Kind == NodeRepository:
+		return fmt.Sprintf("handler %s depends on repository %s; go through a service", from.ID, to.ID)
+	case to.Kind == NodeRepository && from.Domain != "" && from.Domain != to.Domain:
+		return fmt.Sprintf("%s (%s) uses repository %s of the %s domain; depend on its service instead",
+			from.ID, from.Domain, to.ID, to.Domain)
+	}
+	return ""
+}
+
+// findCycles returns every cycle found by a depth-first walk, as closed paths (a -> b -> a)
+func findCycles(nodes []GraphNode, deps map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, dep := range deps[id] {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				for i, onStack := range stack {
+					if onStack == dep {
+						cycle := append(append([]string{}, stack[i:]...), dep)
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+	for _, node := range nodes {
+		if state[node.ID] == unvisited {
+			visit(node.ID)
+		}
+	}
+	return cycles
+}
+
+// typeKind classifies a provided type by the package it is declared in,
+// e.g. repository.UserRepository is a repository
+func typeKind(t reflect.Type) string {
+	switch path.Base(typePackage(t)) {
+	case "handler":
+		return NodeHandler
+	case "service":
+		return NodeService
+	case "repository":
+		return NodeRepository
+	}
+	return NodeComponent
+}
+
+// typeDomain returns the domain of a layered type, the directory holding
+// its package (internal/domain/user/service -> user), or ""
+func typeDomain(t reflect.Type) string {
+	if typeKind(t) == NodeComponent {
+		return ""
+	}
+	return path.Base(path.Dir(typePackage(t)))
+}
+
+// typePackage returns the import path declaring t, looking through pointers
+func typePackage(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath()
+}
+
+// WriteDOT renders the graph in Graphviz DOT; issues are drawn in red
+func (g *DependencyGraph) WriteDOT(w io.Writer) error {
+	flagged := g.flaggedNodes()
+	shapes := map[string]string{NodeRoute: "ellipse", NodeValue: "cylinder", NodeMissing: "octagon"}
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		attrs := []string{fmt.Sprintf("label=%q", node.ID+"\n"+node.Kind)}
+		if shape, ok := shapes[node.Kind]; ok {
+			attrs = append(attrs, "shape="+shape)
+		}
+		if flagged[node.ID] {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(&b, "  %q [%s];\n", node.ID, strings.Join(attrs, ", "))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart; issues are styled red
+func (g *DependencyGraph) WriteMermaid(w io.Writer) error {
+	flagged := g.flaggedNodes()
+	ids := make(map[string]string, len(g.Nodes))
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, node := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.ID] = id
+		label := strings.ReplaceAll(node.ID, `"`, "#quot;")
+		switch node.Kind {
+		case NodeRoute:
+			fmt.Fprintf(&b, "  %s([\"%s\"])\n", id, label)
+		case NodeValue:
+			fmt.Fprintf(&b, "  %s[(\"%s\")]\n", id, label)
+		default:
+			fmt.Fprintf(&b, "  %s[\"%s<br/><i>%s</i>\"]\n", id, label, node.Kind)
+		}
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+	}
+	for _, node := range g.Nodes {
+		if flagged[node.ID] {
+			fmt.Fprintf(&b, "  style %s stroke:#d33,stroke-width:2px\n", ids[node.ID])
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// flaggedNodes returns the nodes named by any issue
+func (g *DependencyGraph) flaggedNodes() map[string]bool {
+	flagged := make(map[string]bool)
+	for _, issue := range g.Issues {
+		for _, id := range issue.Nodes {
+			flagged[id] = true
+		}
+	}
+	return flagged
+}
+
+// registerGraphRoute serves the dependency graph at /_internal/graph?format=json|dot|mermaid
+func (c *Container) registerGraphRoute(router *gin.Engine) {
+	router.GET("/_internal/graph", func(ctx *gin.Context) {
+		graph := c.DependencyGraph()
+
+		switch ctx.DefaultQuery("format", "json") {
+		case "json":
+			ctx.JSON(http.StatusOK, graph)
+		case "dot":
+			ctx.Header("Content-Type", "text/vnd.graphviz; charset=utf-8")
+			ctx.Status(http.StatusOK)
+			graph.WriteDOT(ctx.Writer)
+		case "mermaid":
+			ctx.Header("Content-Type", "text/plain; charset=utf-8")
+			ctx.Status(http.StatusOK)
+			graph.WriteMermaid(ctx.Writer)
+		default:
+			ctx.String(http.StatusBadRequest, "format must be json, dot or mermaid")
+		}
+	})
+}
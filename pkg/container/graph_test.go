@@ -0,0 +1,84 @@
+package container
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// issuesOf returns the messages of g's issues of kind
+func issuesOf(g *DependencyGraph, kind string) []string {
+	var messages []string
+	for _, issue := range g.Issues {
+		if issue.Kind == kind {
+			messages = append(messages, issue.Message)
+		}
+	}
+	return messages
+}
+
+func TestGraphReportsUnusedAndMissingProviders(t *testing.T) {
+	var calls int
+	f := newStoreFactory(t, &calls)
+	if err := f.Provide(func(*cycleB) *cycleA { return &cycleA{} }); err != nil {
+		t.Fatal(err)
+	}
+	// A route to testUsers uses it, so only cycleA is left unused
+	routes := []RouteInfo{{Method: "GET", Path: "/users", handlerType: reflect.TypeOf(&testUsers{})}}
+	g := BuildDependencyGraph(f, routes, "/api")
+
+	unused := issuesOf(g, IssueUnusedProvider)
+	if len(unused) != 1 || !strings.HasPrefix(unused[0], "*container.cycleA ") {
+		t.Errorf("unused providers = %q, want only *container.cycleA", unused)
+	}
+	missing := issuesOf(g, IssueMissingProvider)
+	if want := "no provider for *container.cycleB (required by *container.cycleA)"; len(missing) != 1 || missing[0] != want {
+		t.Errorf("missing providers = %q, want %q", missing, want)
+	}
+	if calls != 0 {
+		t.Error("building the graph ran a constructor")
+	}
+}
+
+func TestGraphReportsCycles(t *testing.T) {
+	f := NewFactory()
+	if err := f.Provide(func(*cycleB) *cycleA { return &cycleA{} }); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Provide(func(*cycleA) *cycleB { return &cycleB{} }); err != nil {
+		t.Fatal(err)
+	}
+
+	cycles := issuesOf(BuildDependencyGraph(f, nil, "/api"), IssueCycle)
+	if len(cycles) != 1 || !strings.Contains(cycles[0], "*container.cycleA -> *container.cycleB -> *container.cycleA") &&
+		!strings.Contains(cycles[0], "*container.cycleB -> *container.cycleA -> *container.cycleB") {
+		t.Fatalf("cycles = %q, want cycleA <-> cycleB", cycles)
+	}
+}
+
+func TestLayeringViolation(t *testing.T) {
+	userHandler := GraphNode{ID: "*handler.UserHandler", Kind: NodeHandler, Domain: "user"}
+	userService := GraphNode{ID: "service.UserService", Kind: NodeService, Domain: "user"}
+	postService := GraphNode{ID: "service.PostService", Kind: NodeService, Domain: "post"}
+	userRepo := GraphNode{ID: "repository.UserRepository", Kind: NodeRepository, Domain: "user"}
+	tokens := GraphNode{ID: "*auth.Tokens", Kind: NodeComponent}
+
+	tests := []struct {
+		name      string
+		from, to  GraphNode
+		violation string
+	}{
+		{"handler to service", userHandler, userService, ""},
+		{"service to own repository", userService, userRepo, ""},
+		{"cross-domain service", postService, userService, ""},
+		{"component to repository", tokens, userRepo, ""},
+		{"handler to repository", userHandler, userRepo, "go through a service"},
+		{"repository of another domain", postService, userRepo, "depend on its service instead"},
+	}
+	for _, tt := range tests {
+		got := layeringViolation(tt.from, tt.to)
+		if tt.violation == "" && got != "" || !strings.Contains(got, tt.violation) {
+			t.Errorf("%s: layeringViolation = %q, want %q", tt.name, got, tt.violation)
+		}
+	}
+}
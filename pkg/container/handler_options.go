@@ -0,0 +1,99 @@
+package container
+
+import (
+	"study-go-controller/pkg/routing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandlerOption customizes how a handler's routes are registered
+type HandlerOption func(*handlerConfig)
+
+// handlerConfig collects the options passed to RegisterHandler
+type handlerConfig struct {
+	version          string
+	convention       routing.RouteConvention
+	resource         string
+	parentPath       string
+	parentParam      string
+	singletons       []string
+	params           map[string]routing.ParamType
+	middleware       []gin.HandlerFunc
+	methodMiddleware map[string][]gin.HandlerFunc
+}
+
+// newHandlerConfig applies options over the defaults
+func newHandlerConfig(opts []HandlerOption) *handlerConfig {
+	cfg := &handlerConfig{
+		version:          defaultVersion,
+		methodMiddleware: make(map[string][]gin.HandlerFunc),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// InVersion registers the handler's routes in an API version other than the default
+func InVersion(version string) HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.version = version
+	}
+}
+
+// WithConvention maps the handler's methods with a specific routing convention,
+// e.g. routing.Chain(routing.NewPrefixConvention(routing.CommonPrefixRules...), routing.RESTConvention{})
+func WithConvention(convention routing.RouteConvention) HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.convention = convention
+	}
+}
+
+// WithResource overrides the singular resource name derived from the base path
+func WithResource(name string) HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.resource = name
+	}
+}
+
+// WithParent nests the handler under a parent resource, e.g.
+// WithParent("/posts", "postId") mounts "/comments" at "/posts/:postId/comments"
+func WithParent(path, param string) HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.parentPath = path
+		cfg.parentParam = param
+	}
+}
+
+// WithSingletons declares sub-resources that exist once per resource, so
+// GetUserProfile maps to /:id/profile instead of /:id/profiles/:profileId
+func WithSingletons(names ...string) HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.singletons = append(cfg.singletons, names...)
+	}
+}
+
+// WithParam declares the type of a path param for every route of the handler,
+// e.g. WithParam("slug", routing.Pattern(`^[a-z0-9-]+$`)); RouteSpec.Param takes precedence
+func WithParam(name string, paramType routing.ParamType) HandlerOption {
+	return func(cfg *handlerConfig) {
+		if cfg.params == nil {
+			cfg.params = make(map[string]routing.ParamType)
+		}
+		cfg.params[name] = paramType
+	}
+}
+
+// WithMiddleware runs middleware before every route of the handler
+func WithMiddleware(middleware ...gin.HandlerFunc) HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.middleware = append(cfg.middleware, middleware...)
+	}
+}
+
+// WithMethodMiddleware runs middleware before the routes of a single handler method
+func WithMethodMiddleware(methodName string, middleware ...gin.HandlerFunc) HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.methodMiddleware[methodName] = append(cfg.methodMiddleware[methodName], middleware...)
+	}
+}
@@ -0,0 +1,114 @@
+package container
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"study-go-controller/pkg/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthCheckTimeout bounds each check so one hanging dependency can't
+// stall the health and readiness endpoints
+const healthCheckTimeout = 2 * time.Second
+
+// ReadinessChecker is implemented by components that can tell whether the
+// app should receive traffic, e.g. a database that is reachable and not
+// lagging behind its primary. Every registered checker is run by
+// Container.CheckReady.
+type ReadinessChecker interface {
+	CheckReady(ctx context.Context) error
+}
+
+var (
+	readinessCheckerType = reflect.TypeOf((*ReadinessChecker)(nil)).Elem()
+	metricsReporterType  = reflect.TypeOf((*metrics.Reporter)(nil)).Elem()
+)
+
+// HealthReport is the outcome of Container.CheckHealth and Container.CheckReady
+type HealthReport struct {
+	Status string            `json:"status"`           // "ok" or "unavailable"
+	Checks map[string]string `json:"checks,omitempty"` // checker name -> "ok" or the error
+	// Stats are the samples of each metrics.Reporter by name, e.g. the connection pool
+	Stats map[string]map[string]float64 `json:"stats,omitempty"`
+}
+
+// CheckHealth reports liveness: the process is up and serving. It runs no
+// checks, so a database blip doesn't get healthy instances restarted, and
+// only adds the registered metrics.
+func (c *Container) CheckHealth(ctx context.Context) HealthReport {
+	report := HealthReport{Status: "ok", Stats: make(map[string]map[string]float64)}
+	for _, registered := range c.Registry.assignableTo(metricsReporterType) {
+		stats := make(map[string]float64)
+		for _, sample := range registered.service.(metrics.Reporter).Metrics() {
+			stats[sample.Name] = sample.Value
+		}
+		report.Stats[registered.name] = stats
+	}
+	return report
+}
+
+// CheckReady runs every registered HealthChecker and ReadinessChecker; the
+// report is "ok" only when all of them pass. A component implementing both
+// only runs CheckReady, which is expected to include its health check.
+func (c *Container) CheckReady(ctx context.Context) HealthReport {
+	report := HealthReport{Status: "ok", Checks: make(map[string]string)}
+	for _, registered := range c.Registry.services {
+		var check func(context.Context) error
+		switch service := registered.service.(type) {
+		case ReadinessChecker:
+			check = service.CheckReady
+		case HealthChecker:
+			check = service.CheckHealth
+		default:
+			continue
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := check(checkCtx)
+		cancel()
+
+		if err != nil {
+			report.Status = "unavailable"
+			report.Checks[registered.name] = err.Error()
+			continue
+		}
+		report.Checks[registered.name] = "ok"
+	}
+	return report
+}
+
+// registerHealthRoutes serves CheckHealth at /health and CheckReady at /ready,
+// with 503 when a check fails
+func (c *Container) registerHealthRoutes(router *gin.Engine) {
+	router.GET("/health", func(ctx *gin.Context) {
+		writeReport(ctx, c.CheckHealth(ctx.Request.Context()))
+	})
+	router.GET("/ready", func(ctx *gin.Context) {
+		writeReport(ctx, c.CheckReady(ctx.Request.Context()))
+	})
+}
+
+// registerMetricsRoute serves the metrics.Reporter samples at /metrics
+func (c *Container) registerMetricsRoute(router *gin.Engine) {
+	router.GET("/metrics", func(ctx *gin.Context) {
+		var samples []metrics.Metric
+		for _, reporter := range ResolveAll[metrics.Reporter](c.Registry) {
+			samples = append(samples, reporter.Metrics()...)
+		}
+		ctx.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		ctx.Status(http.StatusOK)
+		metrics.Write(ctx.Writer, samples)
+	})
+}
+
+// writeReport responds with report, 503 unless it is "ok"
+func writeReport(ctx *gin.Context, report HealthReport) {
+	status := http.StatusOK
+	if report.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, report)
+}
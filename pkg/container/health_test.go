@@ -0,0 +1,61 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"study-go-controller/pkg/config"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// flakyDependency is a HealthChecker whose outcome the test sets
+type flakyDependency struct {
+	err error
+}
+
+func (d *flakyDependency) CheckHealth(ctx context.Context) error { return d.err }
+
+func TestHealthIsLivenessAndReadyRunsTheChecks(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewContainer(WithConfig(&config.Config{}), WithDB(db), WithoutMigrations())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dependency := &flakyDependency{}
+	c.Registry.Register("Dependency", dependency)
+	router := gin.New()
+	c.RegisterRoutes(router)
+
+	get := func(path string) (int, HealthReport) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		var report HealthReport
+		if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		return recorder.Code, report
+	}
+
+	if status, report := get("/ready"); status != http.StatusOK || report.Checks["Dependency"] != "ok" {
+		t.Fatalf("/ready = %d %+v, want the dependency check passing", status, report)
+	}
+
+	// A lost dependency makes the app unready, but it is still alive
+	dependency.err = errors.New("connection refused")
+	if status, report := get("/ready"); status != http.StatusServiceUnavailable || report.Checks["Dependency"] != "connection refused" {
+		t.Fatalf("/ready with the dependency down = %d %+v, want 503", status, report)
+	}
+	if status, report := get("/health"); status != http.StatusOK || report.Status != "ok" || len(report.Checks) != 0 {
+		t.Fatalf("/health with the dependency down = %d %+v, want 200 without checks", status, report)
+	}
+}
@@ -0,0 +1,71 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Starter is implemented by components that need to start after the
+// container is built, e.g. background workers
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by components that hold resources to release on
+// shutdown, e.g. connection pools
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Start starts every Starter singleton in construction order, so each
+// component starts after its dependencies. At the first failure it stops
+// the components built before the failing one in reverse order, the same
+// way Stop does, and returns the failure; don't call Stop afterwards.
+func (c *Container) Start(ctx context.Context) error {
+	singletons := c.Factory.Singletons()
+	for i, component := range singletons {
+		starter, ok := component.(Starter)
+		if !ok {
+			continue
+		}
+
+		started := time.Now()
+		if err := starter.Start(ctx); err != nil {
+			log.Printf("❌ Failed to start %T: %v", component, err)
+			err = fmt.Errorf("failed to start %T: %w", component, err)
+			return errors.Join(err, stopAll(ctx, singletons[:i]))
+		}
+		log.Printf("▶️  Started %T (%s)", component, time.Since(started))
+	}
+	return nil
+}
+
+// Stop stops every Stopper singleton in reverse construction order, so the
+// database, which everything depends on, is closed last. A failing
+// component doesn't keep the others from stopping; all failures are returned.
+func (c *Container) Stop(ctx context.Context) error {
+	return stopAll(ctx, c.Factory.Singletons())
+}
+
+// stopAll stops the Stoppers among components, last one first
+func stopAll(ctx context.Context, components []interface{}) error {
+	var errs []error
+	for i := len(components) - 1; i >= 0; i-- {
+		stopper, ok := components[i].(Stopper)
+		if !ok {
+			continue
+		}
+
+		started := time.Now()
+		if err := stopper.Stop(ctx); err != nil {
+			log.Printf("❌ Failed to stop %T: %v", stopper, err)
+			errs = append(errs, fmt.Errorf("failed to stop %T: %w", stopper, err))
+			continue
+		}
+		log.Printf("⏹️  Stopped %T (%s)", stopper, time.Since(started))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,100 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// lifecycleLog records Start and Stop calls and fails the starts it is told to
+type lifecycleLog struct {
+	events    []string
+	failStart string
+}
+
+// component is a fake Starter and Stopper named in the log
+type component struct {
+	name string
+	log  *lifecycleLog
+}
+
+func (c *component) Start(ctx context.Context) error {
+	if c.log.failStart == c.name {
+		return errors.New("boom")
+	}
+	c.log.events = append(c.log.events, "start "+c.name)
+	return nil
+}
+
+func (c *component) Stop(ctx context.Context) error {
+	c.log.events = append(c.log.events, "stop "+c.name)
+	return nil
+}
+
+// fakeCache and fakeWorker start and stop
+type (
+	fakeCache  struct{ component }
+	fakeWorker struct{ component }
+)
+
+// fakePool only stops, like the database
+type fakePool struct {
+	log *lifecycleLog
+}
+
+func (p *fakePool) Stop(ctx context.Context) error {
+	p.log.events = append(p.log.events, "stop pool")
+	return nil
+}
+
+// newLifecycleContainer builds pool <- cache <- worker, resolving the worker
+// first so construction order comes from the dependencies
+func newLifecycleContainer(t *testing.T, events *lifecycleLog) *Container {
+	t.Helper()
+	f := NewFactory()
+	constructors := []interface{}{
+		func(*fakeCache) *fakeWorker { return &fakeWorker{component{"worker", events}} },
+		func(*fakePool) *fakeCache { return &fakeCache{component{"cache", events}} },
+		func() *fakePool { return &fakePool{events} },
+	}
+	for _, constructor := range constructors {
+		if err := f.Provide(constructor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := f.Get(reflect.TypeOf(&fakeWorker{})); err != nil {
+		t.Fatal(err)
+	}
+	return &Container{Factory: f}
+}
+
+func TestStartInConstructionOrderAndStopInReverse(t *testing.T) {
+	events := &lifecycleLog{}
+	c := newLifecycleContainer(t, events)
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"start cache", "start worker", "stop worker", "stop cache", "stop pool"}
+	if !equalStrings(events.events, want) {
+		t.Fatalf("events = %v, want %v", events.events, want)
+	}
+}
+
+func TestFailedStartStopsWhatStarted(t *testing.T) {
+	events := &lifecycleLog{failStart: "worker"}
+	c := newLifecycleContainer(t, events)
+
+	if err := c.Start(context.Background()); err == nil {
+		t.Fatal("Start succeeded with a failing worker")
+	}
+	// The worker never started, so it isn't stopped either
+	want := []string{"start cache", "stop cache", "stop pool"}
+	if !equalStrings(events.events, want) {
+		t.Fatalf("events = %v, want %v", events.events, want)
+	}
+}
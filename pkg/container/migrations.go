@@ -0,0 +1,73 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"study-go-controller/pkg/migrate"
+
+	"gorm.io/gorm"
+)
+
+// Modes of config.Config.MigrateOnStart
+const (
+	// MigrateCheck refuses to start while migrations are pending; the default
+	MigrateCheck = "check"
+	// MigrateUp applies pending migrations before starting
+	MigrateUp = "up"
+	// MigrateOff starts without looking at migrations
+	MigrateOff = "off"
+)
+
+// NewMigrator returns a migrator for the migrations of modules on db's dialect
+func NewMigrator(db *gorm.DB, modules []Module) (*migrate.Migrator, error) {
+	migrations, err := moduleMigrations(modules, db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(db, migrations)
+}
+
+// migrateOnStart checks or applies the modules' migrations according to mode
+func migrateOnStart(db *gorm.DB, modules []Module, mode string) error {
+	switch mode {
+	case MigrateOff:
+		log.Println("⚠️ Skipping migration check (MIGRATE_ON_START=off)")
+		return nil
+	case MigrateCheck, MigrateUp:
+	default:
+		return fmt.Errorf("unknown MIGRATE_ON_START %q (want %s, %s or %s)", mode, MigrateCheck, MigrateUp, MigrateOff)
+	}
+
+	migrator, err := NewMigrator(db, modules)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if mode == MigrateUp {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("✅ Migrations up to date (%d applied)", applied)
+		return nil
+	}
+
+	if err := migrator.Verify(ctx); err != nil {
+		return err
+	}
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		ids := make([]string, len(pending))
+		for i, migration := range pending {
+			ids[i] = migration.ID()
+		}
+		return fmt.Errorf("%d pending migrations (%s); run \"server migrate up\" or set MIGRATE_ON_START=up",
+			len(pending), strings.Join(ids, ", "))
+	}
+	return nil
+}
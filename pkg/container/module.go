@@ -0,0 +1,110 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"study-go-controller/pkg/migrate"
+	"sync"
+)
+
+// Module is a domain that plugs itself into the container. Implementations
+// live in the domain's own package and call RegisterModule from init, so the
+// container never imports domain code; importing the package enables it:
+//
+//	import _ "study-go-controller/internal/domain/user"
+type Module interface {
+	// Name identifies the module in logs and in config.Config.DisabledModules, e.g. "users"
+	Name() string
+	// BasePath is where the module's handlers are mounted, e.g. "/users"
+	BasePath() string
+	// Migrations are the versioned schema changes of the module's tables on
+	// dialect (mysql, postgres or sqlite), see pkg/migrate
+	Migrations(dialect string) ([]migrate.Migration, error)
+	// Providers are constructors for the module's repositories, services and
+	// handlers; repositories are registered request-scoped (see AsScoped),
+	// the rest as singletons
+	Providers() []interface{}
+	// Handlers lists the handlers to route, see Mount
+	Handlers() []HandlerMount
+}
+
+// HandlerMount routes a handler built by one of the module's providers
+type HandlerMount struct {
+	Type    reflect.Type
+	Options []HandlerOption
+}
+
+// Mount routes the handler type of handler, a typed nil pointer, at the
+// module's base path:
+//
+//	container.Mount((*handler.UserHandler)(nil), container.WithSingletons("Profile"))
+func Mount(handler interface{}, opts ...HandlerOption) HandlerMount {
+	return HandlerMount{Type: reflect.TypeOf(handler), Options: opts}
+}
+
+var (
+	modulesMu sync.Mutex
+	modules   = make(map[string]Module)
+)
+
+// RegisterModule makes a module available to containers. It panics when
+// called twice with the same name, like database/sql.Register.
+func RegisterModule(module Module) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	name := module.Name()
+	if _, exists := modules[name]; exists {
+		panic(fmt.Sprintf("container: module %q registered twice", name))
+	}
+	modules[name] = module
+}
+
+// Modules returns every registered module sorted by name
+func Modules() []Module {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	list := make([]Module, 0, len(modules))
+	for _, module := range modules {
+		list = append(list, module)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// EnabledModules returns the registered modules minus the disabled ones,
+// e.g. config.Config.DisabledModules
+func EnabledModules(disabledNames []string) ([]Module, error) {
+	disabled := make(map[string]bool)
+	for _, name := range disabledNames {
+		disabled[name] = true
+	}
+
+	var enabled []Module
+	for _, module := range Modules() {
+		if disabled[module.Name()] {
+			delete(disabled, module.Name())
+			continue
+		}
+		enabled = append(enabled, module)
+	}
+	for name := range disabled {
+		return nil, fmt.Errorf("cannot disable unknown module %q", name)
+	}
+	return enabled, nil
+}
+
+// moduleMigrations collects the migrations of modules on dialect
+func moduleMigrations(modules []Module, dialect string) ([]migrate.Migration, error) {
+	var migrations []migrate.Migration
+	for _, module := range modules {
+		moduleMigrations, err := module.Migrations(dialect)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", module.Name(), err)
+		}
+		migrations = append(migrations, moduleMigrations...)
+	}
+	return migrations, nil
+}
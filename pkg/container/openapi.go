@@ -0,0 +1,231 @@
+package container
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"study-go-controller/pkg/batch"
+	"study-go-controller/pkg/openapi"
+	"study-go-controller/pkg/patch"
+	"study-go-controller/pkg/response"
+	"study-go-controller/pkg/routing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiInfo describes the API in the generated OpenAPI document
+var apiInfo = openapi.Info{
+	Title:   "Study Go Controller API",
+	Version: "1.0.0",
+}
+
+// batchResultType marks batch routes, which answer with batch.Respond
+var batchResultType = reflect.TypeOf(batch.Result{})
+
+// OpenAPI builds an OpenAPI 3.1 document from the registered routes
+func (c *Container) OpenAPI() *openapi.Document {
+	return BuildOpenAPI(c.AutoRouter.GetRoutes(), apiPrefix)
+}
+
+// BuildOpenAPI generates an OpenAPI document for versioned routes mounted under prefix
+func BuildOpenAPI(routes []RouteInfo, prefix string) *openapi.Document {
+	doc := openapi.NewDocument(apiInfo)
+	schemas := openapi.NewSchemaRegistry()
+	envelope := schemas.SchemaFor(reflect.TypeOf(response.APIResponse{}))
+	operationIDs := make(map[string]int)
+
+	for _, route := range routes {
+		path := openAPIPath(versionedPath(prefix, route))
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &openapi.PathItem{}
+			doc.Paths[path] = item
+		}
+		op := buildOperation(route, schemas, envelope)
+		// A method routed at several paths gets numbered IDs
+		operationIDs[op.OperationID]++
+		if n := operationIDs[op.OperationID]; n > 1 {
+			op.OperationID = fmt.Sprintf("%s_%d", op.OperationID, n)
+		}
+		item.SetOperation(route.Method, op)
+	}
+
+	doc.Components.Schemas = schemas.Schemas()
+	return doc
+}
+
+// buildOperation describes a single route
+func buildOperation(route RouteInfo, schemas *openapi.SchemaRegistry, envelope *openapi.Schema) *openapi.Operation {
+	op := &openapi.Operation{
+		OperationID: route.Version + "_" + route.HandlerName + "_" + route.MethodName,
+		Summary:     route.Summary,
+		Tags:        []string{strings.TrimSuffix(route.HandlerName, "Handler")},
+		Responses:   make(map[string]*openapi.Response),
+		Deprecated:  route.Deprecation != nil,
+	}
+
+	for _, name := range route.Params {
+		op.Parameters = append(op.Parameters, openapi.Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   pathParamSchema(route.ParamTypes[name]),
+		})
+	}
+
+	if route.RequestType != nil {
+		if hasRequestBody(route.Method) {
+			schema := schemas.SchemaFor(route.RequestType)
+			content := jsonContent(schema)
+			if route.Method == http.MethodPatch {
+				content = patchContent(schema)
+			}
+			op.RequestBody = &openapi.RequestBody{Required: true, Content: content}
+		} else {
+			op.Parameters = append(op.Parameters, queryParams(route.RequestType, schemas)...)
+		}
+	}
+
+	// Successful responses wrap the payload in the standard envelope
+	success := envelope
+	if route.ResponseType != nil {
+		success = &openapi.Schema{AllOf: []*openapi.Schema{
+			envelope,
+			{Type: "object", Properties: map[string]*openapi.Schema{
+				"data": schemas.SchemaFor(route.ResponseType),
+			}},
+		}}
+	}
+
+	// Batches report failed items with the same payload, see batch.Respond
+	status := route.SuccessStatus
+	if status == 0 {
+		status = defaultSuccessStatus(route.Method, route.MethodName)
+	}
+	successStatuses := []int{status}
+	if route.ResponseType == batchResultType {
+		successStatuses = append(successStatuses, http.StatusMultiStatus, http.StatusFailedDependency)
+	}
+	for _, code := range successStatuses {
+		op.Responses[strconv.Itoa(code)] = &openapi.Response{
+			Description: http.StatusText(code),
+			Content:     jsonContent(success),
+		}
+	}
+
+	errorStatuses := []int{http.StatusBadRequest, http.StatusInternalServerError}
+	if len(route.Params) > 0 {
+		errorStatuses = append(errorStatuses, http.StatusNotFound)
+	}
+	if route.Method == http.MethodPatch {
+		errorStatuses = append(errorStatuses, http.StatusConflict, http.StatusUnsupportedMediaType)
+	}
+	for _, code := range errorStatuses {
+		op.Responses[strconv.Itoa(code)] = &openapi.Response{
+			Description: http.StatusText(code),
+			Content:     jsonContent(envelope),
+		}
+	}
+
+	return op
+}
+
+// queryParams lists form-tagged fields of a request type as query parameters
+func queryParams(t reflect.Type, schemas *openapi.SchemaRegistry) []openapi.Parameter {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []openapi.Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		params = append(params, openapi.Parameter{
+			Name:     name,
+			In:       "query",
+			Required: strings.Contains(field.Tag.Get("binding"), "required"),
+			Schema:   schemas.SchemaFor(field.Type),
+		})
+	}
+	return params
+}
+
+// versionedPath returns the path a route is served at, e.g. /api/v1/users
+func versionedPath(prefix string, route RouteInfo) string {
+	return prefix + "/" + route.Version + route.Path
+}
+
+// openAPIPath converts gin path params (:id, *path) to OpenAPI templates ({id})
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segmentKind(segment) != 0 {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// pathParamSchema describes a path param of the given type
+func pathParamSchema(paramType routing.ParamType) *openapi.Schema {
+	switch paramType.Name {
+	case routing.Uint.Name:
+		return &openapi.Schema{Type: "integer", Minimum: new(float64)}
+	case routing.Int.Name:
+		return &openapi.Schema{Type: "integer"}
+	case routing.UUID.Name:
+		return &openapi.Schema{Type: "string", Format: "uuid"}
+	default:
+		return &openapi.Schema{Type: "string", Pattern: paramType.Pattern}
+	}
+}
+
+// hasRequestBody reports whether requests with this method carry a JSON body
+func hasRequestBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+// patchContent describes the patch formats accepted by PATCH routes:
+// a merge patch shaped like the resource, or a list of JSON Patch operations
+func patchContent(resource *openapi.Schema) map[string]openapi.MediaType {
+	operation := &openapi.Schema{
+		Type:     "object",
+		Required: []string{"op", "path"},
+		Properties: map[string]*openapi.Schema{
+			"op":    {Type: "string", Enum: []interface{}{"add", "remove", "replace", "move", "copy", "test"}},
+			"path":  {Type: "string"},
+			"from":  {Type: "string"},
+			"value": {},
+		},
+	}
+	return map[string]openapi.MediaType{
+		patch.MergePatchType: {Schema: resource},
+		patch.JSONPatchType:  {Schema: &openapi.Schema{Type: "array", Items: operation}},
+	}
+}
+
+// jsonContent wraps a schema as application/json content
+func jsonContent(schema *openapi.Schema) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{"application/json": {Schema: schema}}
+}
+
+// registerDocsRoutes serves the OpenAPI document and the docs page
+func (c *Container) registerDocsRoutes(router *gin.Engine) {
+	spec := c.OpenAPI()
+
+	router.GET("/openapi.json", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, spec)
+	})
+	router.GET("/docs", func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8",
+			[]byte(openapi.DocsPage(apiInfo.Title, "/openapi.json")))
+	})
+}
@@ -0,0 +1,197 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"study-go-controller/pkg/batch"
+	"study-go-controller/pkg/openapi"
+	"study-go-controller/pkg/patch"
+	"study-go-controller/pkg/routing"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// widgetRequest is the typed request of GetWidget
+type widgetRequest struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// widgetQuery is documented as query parameters of Fetch
+type widgetQuery struct {
+	Color string `form:"color" binding:"required,oneof=red blue"`
+	Page  int    `form:"page"`
+}
+
+// widgetBody is documented as the request body of write routes
+type widgetBody struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// widgetHandler covers the shapes of operations the document describes
+type widgetHandler struct{}
+
+func (h *widgetHandler) Routes() []routing.RouteSpec {
+	return []routing.RouteSpec{
+		routing.Action("CreateWidget").Accepts(widgetBody{}).Returns(widgetBody{}),
+		routing.Action("PatchWidget").Accepts(widgetBody{}).Returns(widgetBody{}),
+		routing.Action("BatchCreateWidgets").Returns(batch.Result{}).WithStatus(http.StatusCreated),
+		routing.Action("BatchUpdateWidgets").Returns(batch.Result{}),
+		routing.GET("/search", "Fetch").Accepts(widgetQuery{}),
+		routing.GET("/find", "Fetch").Accepts(widgetQuery{}),
+	}
+}
+
+func (h *widgetHandler) CreateWidget(c *gin.Context)       {}
+func (h *widgetHandler) PatchWidget(c *gin.Context)        {}
+func (h *widgetHandler) BatchCreateWidgets(c *gin.Context) {}
+func (h *widgetHandler) BatchUpdateWidgets(c *gin.Context) {}
+func (h *widgetHandler) Fetch(c *gin.Context)              {}
+
+func (h *widgetHandler) GetWidget(ctx context.Context, req *widgetRequest) (*widgetBody, error) {
+	return &widgetBody{}, nil
+}
+
+// widgetDocument builds the document of widgetHandler and a thingHandler,
+// which also has a Fetch method
+func widgetDocument(t *testing.T) *openapi.Document {
+	t.Helper()
+	router := NewAutoRouter()
+	if err := router.RegisterHandler("/widgets", &widgetHandler{}); err != nil {
+		t.Fatal(err)
+	}
+	things := &thingHandler{routes: []routing.RouteSpec{routing.GET("/:id", "Fetch")}}
+	if err := router.RegisterHandler("/things", things); err != nil {
+		t.Fatal(err)
+	}
+	return BuildOpenAPI(router.GetRoutes(), apiPrefix)
+}
+
+// responseCodes lists the documented statuses of an operation
+func responseCodes(op *openapi.Operation) []string {
+	var codes []string
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func TestOpenAPIDocumentsSuccessStatuses(t *testing.T) {
+	doc := widgetDocument(t)
+	tests := []struct {
+		name  string
+		op    *openapi.Operation
+		codes []string
+	}{
+		{"create", doc.Paths["/api/v1/widgets"].Post, []string{"201", "400", "500"}},
+		{"typed get", doc.Paths["/api/v1/widgets/{id}"].Get, []string{"200", "400", "404", "500"}},
+		{"patch", doc.Paths["/api/v1/widgets/{id}"].Patch, []string{"200", "400", "404", "409", "415", "500"}},
+		{"batch create", doc.Paths["/api/v1/widgets/batch-create"].Post, []string{"201", "207", "400", "424", "500"}},
+		{"batch update", doc.Paths["/api/v1/widgets/batch-update"].Post, []string{"200", "207", "400", "424", "500"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.op == nil {
+				t.Fatal("operation is missing")
+			}
+			if codes := responseCodes(tt.op); !equalStrings(codes, tt.codes) {
+				t.Errorf("responses = %v, want %v", codes, tt.codes)
+			}
+		})
+	}
+
+	// Failed batches carry the same payload as successful ones
+	batchCreate := doc.Paths["/api/v1/widgets/batch-create"].Post
+	if batchCreate.Responses["207"].Content["application/json"].Schema != batchCreate.Responses["201"].Content["application/json"].Schema {
+		t.Error("207 response doesn't describe the batch result")
+	}
+}
+
+func TestOpenAPIOperationIDsAreUnique(t *testing.T) {
+	doc := widgetDocument(t)
+
+	seen := make(map[string]string)
+	for path, item := range doc.Paths {
+		for _, op := range []*openapi.Operation{item.Get, item.Post, item.Put, item.Patch, item.Delete} {
+			if op == nil {
+				continue
+			}
+			if other, ok := seen[op.OperationID]; ok {
+				t.Errorf("operationId %s is used by %s and %s", op.OperationID, other, path)
+			}
+			seen[op.OperationID] = path
+		}
+	}
+
+	want := map[string]string{
+		"/api/v1/widgets/search": "v1_widgetHandler_Fetch",
+		"/api/v1/widgets/find":   "v1_widgetHandler_Fetch_2",
+		"/api/v1/things/{id}":    "v1_thingHandler_Fetch",
+	}
+	for path, id := range want {
+		if got := doc.Paths[path].Get.OperationID; got != id {
+			t.Errorf("GET %s operationId = %s, want %s", path, got, id)
+		}
+	}
+}
+
+func TestOpenAPIDescribesParametersAndBodies(t *testing.T) {
+	doc := widgetDocument(t)
+
+	get := doc.Paths["/api/v1/widgets/{id}"].Get
+	if len(get.Parameters) != 1 || get.Parameters[0].In != "path" || get.Parameters[0].Schema.Type != "integer" {
+		t.Errorf("GET /widgets/{id} parameters = %+v, want an integer path param", get.Parameters)
+	}
+
+	search := doc.Paths["/api/v1/widgets/search"].Get
+	if len(search.Parameters) != 2 || search.Parameters[0].Name != "color" || !search.Parameters[0].Required ||
+		search.Parameters[1].Name != "page" || search.Parameters[1].Required {
+		t.Errorf("GET /widgets/search parameters = %+v, want required color and optional page", search.Parameters)
+	}
+
+	create := doc.Paths["/api/v1/widgets"].Post
+	if create.RequestBody == nil || create.RequestBody.Content["application/json"].Schema == nil {
+		t.Fatalf("POST /widgets request body = %+v", create.RequestBody)
+	}
+
+	patchOp := doc.Paths["/api/v1/widgets/{id}"].Patch
+	for _, contentType := range []string{patch.MergePatchType, patch.JSONPatchType} {
+		if _, ok := patchOp.RequestBody.Content[contentType]; !ok {
+			t.Errorf("PATCH /widgets/{id} doesn't accept %s", contentType)
+		}
+	}
+
+	// The document is valid JSON with the referenced component schemas
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.OpenAPI != openapi.Version || len(decoded.Components.Schemas) == 0 {
+		t.Errorf("document = openapi %q with %d schemas", decoded.OpenAPI, len(decoded.Components.Schemas))
+	}
+}
+
+// equalStrings reports whether a and b hold the same strings in order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
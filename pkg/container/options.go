@@ -0,0 +1,69 @@
+package container
+
+import (
+	"reflect"
+	"study-go-controller/pkg/config"
+
+	"gorm.io/gorm"
+)
+
+// Option customizes NewContainer; production wiring needs none
+type Option func(*options)
+
+// options collects the settings of NewContainer
+type options struct {
+	config     *config.Config
+	db         *gorm.DB
+	migrations bool
+	overrides  []override
+}
+
+// override replaces the provider of a type with a fixed value
+type override struct {
+	typ   reflect.Type
+	value interface{}
+}
+
+// WithConfig uses cfg instead of loading the configuration from the environment
+func WithConfig(cfg *config.Config) Option {
+	return func(o *options) { o.config = cfg }
+}
+
+// WithDB uses an existing connection, e.g. in-memory SQLite in tests, instead
+// of connecting with the configured settings. The caller keeps ownership:
+// Container.Stop doesn't close it.
+func WithDB(db *gorm.DB) Option {
+	return func(o *options) { o.db = db }
+}
+
+// WithoutMigrations skips checking and applying the modules' migrations on
+// startup, whatever config.Config.MigrateOnStart says
+func WithoutMigrations() Option {
+	return func(o *options) { o.migrations = false }
+}
+
+// WithOverride replaces whatever provides T with value, typically a fake:
+//
+//	container.NewContainer(
+//		container.WithDB(testDB),
+//		container.WithOverride[repository.UserRepository](fakeUsers),
+//	)
+//
+// Everything depending on T receives value instead.
+func WithOverride[T any](value T) Option {
+	return func(o *options) {
+		o.overrides = append(o.overrides, override{typ: reflect.TypeOf((*T)(nil)).Elem(), value: value})
+	}
+}
+
+// newOptions applies opts over the defaults
+func newOptions(opts []Option) *options {
+	o := &options{migrations: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.config == nil {
+		o.config = config.Load()
+	}
+	return o
+}
@@ -1,42 +1,79 @@
 package container
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// RouteRegistrar defines interface for route registration
+// RouteRegistrar mounts routes the AutoRouter conventions can't express,
+// e.g. webhooks or file downloads. Every registered component implementing
+// it is mounted by SetupAllRoutes under /api, next to the AutoRouter routes;
+// requests still get a scope, see ScopeFrom.
 type RouteRegistrar interface {
 	RegisterRoutes(router *gin.RouterGroup)
 }
 
-// ServiceRegistry manages service registration
+// HealthChecker is implemented by components that can tell whether they are
+// able to serve requests, e.g. a database pinging its connection. Every
+// registered checker is run by Container.CheckReady; /health stays a
+// liveness probe that checks nothing.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+var (
+	routeRegistrarType = reflect.TypeOf((*RouteRegistrar)(nil)).Elem()
+	healthCheckerType  = reflect.TypeOf((*HealthChecker)(nil)).Elem()
+)
+
+// ServiceRegistry holds named components in registration order, so they can
+// be looked up by name, by type (see Resolve) or by an interface they
+// implement (see ResolveAll)
 type ServiceRegistry struct {
-	services    map[string]interface{}
+	services    []registeredService
+	index       map[string]int
 	routeSetups []RouteSetupFunc
 }
 
+// registeredService is a component and the name it was registered under
+type registeredService struct {
+	name    string
+	service interface{}
+}
+
 // RouteSetupFunc defines function signature for route setup
 type RouteSetupFunc func(router *gin.RouterGroup, container *Container)
 
 // NewServiceRegistry creates a new service registry
 func NewServiceRegistry() *ServiceRegistry {
 	return &ServiceRegistry{
-		services:    make(map[string]interface{}),
+		index:       make(map[string]int),
 		routeSetups: make([]RouteSetupFunc, 0),
 	}
 }
 
-// Register registers a service in the registry
+// Register registers a service in the registry, replacing any service
+// registered under the same name
 func (r *ServiceRegistry) Register(name string, service interface{}) {
-	r.services[name] = service
+	if i, exists := r.index[name]; exists {
+		r.services[i].service = service
+		return
+	}
+	r.index[name] = len(r.services)
+	r.services = append(r.services, registeredService{name: name, service: service})
 }
 
 // Get retrieves a service from the registry
 func (r *ServiceRegistry) Get(name string) (interface{}, bool) {
-	service, exists := r.services[name]
-	return service, exists
+	i, exists := r.index[name]
+	if !exists {
+		return nil, false
+	}
+	return r.services[i].service, true
 }
 
 // RegisterRouteSetup registers a route setup function
@@ -44,20 +81,87 @@ func (r *ServiceRegistry) RegisterRouteSetup(setup RouteSetupFunc) {
 	r.routeSetups = append(r.routeSetups, setup)
 }
 
-// SetupAllRoutes sets up all registered routes
+// SetupAllRoutes runs the registered route setup functions, then mounts
+// every registered RouteRegistrar
 func (r *ServiceRegistry) SetupAllRoutes(router *gin.RouterGroup, container *Container) {
 	for _, setup := range r.routeSetups {
 		setup(router, container)
 	}
+	for _, registrar := range ResolveAll[RouteRegistrar](r) {
+		registrar.RegisterRoutes(router)
+	}
 }
 
-// AutoRegister automatically registers services based on reflection
+// AutoRegister registers services under their type names, e.g. "UserHandler"
+// for a *handler.UserHandler
 func (r *ServiceRegistry) AutoRegister(services ...interface{}) {
 	for _, service := range services {
-		serviceName := reflect.TypeOf(service).Elem().Name()
-		r.Register(serviceName, service)
+		r.Register(serviceName(service), service)
+	}
+}
+
+// serviceName returns the name of a service's type without pointers
+func serviceName(service interface{}) string {
+	t := reflect.TypeOf(service)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
 	}
+	return t.Name()
 }
 
-// Example usage:
-// registry.AutoRegister(userService, postService, userHandler, postHandler)
+// Resolve returns the one registered service assignable to T; T is usually
+// an interface, so callers don't type-assert:
+//
+//	userService, err := container.Resolve[service.UserService](c.Registry)
+//
+// It fails when no service or more than one service matches.
+func Resolve[T any](r *ServiceRegistry) (T, error) {
+	var zero T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	matches := r.assignableTo(t)
+	switch len(matches) {
+	case 0:
+		return zero, fmt.Errorf("no registered service is assignable to %s", t)
+	case 1:
+		return matches[0].service.(T), nil
+	}
+
+	names := make([]string, len(matches))
+	for i, match := range matches {
+		names[i] = match.name
+	}
+	return zero, fmt.Errorf("%d registered services are assignable to %s: %s; use ResolveAll or Get",
+		len(matches), t, strings.Join(names, ", "))
+}
+
+// MustResolve is like Resolve but panics on failure; use it where a missing
+// service is a programming error, e.g. while wiring routes
+func MustResolve[T any](r *ServiceRegistry) T {
+	service, err := Resolve[T](r)
+	if err != nil {
+		panic(err)
+	}
+	return service
+}
+
+// ResolveAll returns every registered service assignable to T in
+// registration order, e.g. ResolveAll[HealthChecker](registry)
+func ResolveAll[T any](r *ServiceRegistry) []T {
+	matches := r.assignableTo(reflect.TypeOf((*T)(nil)).Elem())
+	services := make([]T, len(matches))
+	for i, match := range matches {
+		services[i] = match.service.(T)
+	}
+	return services
+}
+
+// assignableTo returns the services whose dynamic type is assignable to t
+func (r *ServiceRegistry) assignableTo(t reflect.Type) []registeredService {
+	var matches []registeredService
+	for _, registered := range r.services {
+		if registered.service != nil && reflect.TypeOf(registered.service).AssignableTo(t) {
+			matches = append(matches, registered)
+		}
+	}
+	return matches
+}
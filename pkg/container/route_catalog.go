@@ -0,0 +1,113 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteDescriptor is the serializable view of a registered route
+type RouteDescriptor struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Version    string   `json:"version"`
+	Handler    string   `json:"handler"`
+	Action     string   `json:"action"`
+	Middleware []string `json:"middleware,omitempty"`
+	Request    string   `json:"request,omitempty"`
+	Response   string   `json:"response,omitempty"`
+	Deprecated bool     `json:"deprecated,omitempty"`
+}
+
+// closureSuffix matches the suffix Go gives closures, e.g. ".func1" or ".func2.1"
+var closureSuffix = regexp.MustCompile(`(\.func\d+)(\.\d+)*$`)
+
+// RouteCatalog describes every served route with its full path, e.g. /api/v1/users
+func (c *Container) RouteCatalog() []RouteDescriptor {
+	return BuildRouteCatalog(c.AutoRouter.GetRoutes(), apiPrefix)
+}
+
+// BuildRouteCatalog describes routes served under prefix
+func BuildRouteCatalog(routes []RouteInfo, prefix string) []RouteDescriptor {
+	catalog := make([]RouteDescriptor, 0, len(routes))
+	for _, route := range routes {
+		descriptor := RouteDescriptor{
+			Method:     route.Method,
+			Path:       versionedPath(prefix, route),
+			Version:    route.Version,
+			Handler:    route.HandlerName,
+			Action:     route.MethodName,
+			Deprecated: route.Deprecation != nil,
+		}
+		for _, middleware := range route.Middleware {
+			descriptor.Middleware = append(descriptor.Middleware, funcName(middleware))
+		}
+		if route.RequestType != nil {
+			descriptor.Request = route.RequestType.String()
+		}
+		if route.ResponseType != nil {
+			descriptor.Response = route.ResponseType.String()
+		}
+		catalog = append(catalog, descriptor)
+	}
+	return catalog
+}
+
+// WriteRouteTable writes the catalog as an aligned text table
+func WriteRouteTable(w io.Writer, catalog []RouteDescriptor) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "METHOD\tPATH\tHANDLER\tMIDDLEWARE\tREQUEST\tRESPONSE")
+	for _, route := range catalog {
+		path := route.Path
+		if route.Deprecated {
+			path += " (deprecated)"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s.%s\t%s\t%s\t%s\n",
+			route.Method, path, route.Handler, route.Action,
+			orDash(strings.Join(route.Middleware, ", ")), orDash(route.Request), orDash(route.Response))
+	}
+	return table.Flush()
+}
+
+// registerInternalRoutes serves the route catalog at /_internal/routes?format=json|text
+func (c *Container) registerInternalRoutes(router *gin.Engine) {
+	router.GET("/_internal/routes", func(ctx *gin.Context) {
+		catalog := c.RouteCatalog()
+
+		switch ctx.DefaultQuery("format", "json") {
+		case "json":
+			ctx.JSON(http.StatusOK, catalog)
+		case "text":
+			ctx.Header("Content-Type", "text/plain; charset=utf-8")
+			ctx.Status(http.StatusOK)
+			WriteRouteTable(ctx.Writer, catalog)
+		default:
+			ctx.String(http.StatusBadRequest, "format must be json or text")
+		}
+	})
+}
+
+// funcName returns a readable name for a handler func,
+// e.g. "middleware.RateLimit" for a closure returned by middleware.RateLimit
+func funcName(fn gin.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return closureSuffix.ReplaceAllString(name, "")
+}
+
+// orDash returns s, or "-" when it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RouteConflict describes two routes that cannot be registered together
+type RouteConflict struct {
+	Reason string
+	First  RouteInfo
+	Second RouteInfo
+}
+
+// RouteConflictError reports every conflict found in the route table
+type RouteConflictError struct {
+	Conflicts []RouteConflict
+}
+
+// Error implements the error interface
+func (e *RouteConflictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "found %d route conflict(s):", len(e.Conflicts))
+	for _, conflict := range e.Conflicts {
+		fmt.Fprintf(&b, "\n  - [%s] %s: %s (%s %s) vs %s (%s %s)",
+			conflict.First.Version, conflict.Reason,
+			routeOwner(conflict.First), conflict.First.Method, conflict.First.Path,
+			routeOwner(conflict.Second), conflict.Second.Method, conflict.Second.Path)
+	}
+	return b.String()
+}
+
+// Validate checks the whole route table for conflicts before anything is
+// registered, so gin never panics halfway through registration
+func (ar *AutoRouter) Validate() error {
+	if err := ar.validateVersions(); err != nil {
+		return err
+	}
+
+	routes := ar.GetRoutes()
+	for _, route := range routes {
+		if !isSupportedMethod(route.Method) {
+			return fmt.Errorf("%s declares unsupported HTTP method %q for %s",
+				routeOwner(route), route.Method, route.Path)
+		}
+	}
+
+	var conflicts []RouteConflict
+	for i := 0; i < len(routes); i++ {
+		for j := i + 1; j < len(routes); j++ {
+			if reason := findRouteConflict(routes[i], routes[j]); reason != "" {
+				conflicts = append(conflicts, RouteConflict{
+					Reason: reason,
+					First:  routes[i],
+					Second: routes[j],
+				})
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return &RouteConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// findRouteConflict returns why two routes clash, or an empty string.
+//
+// Routes of one method share gin's radix tree for that method. Routes of
+// different methods still meet in the OPTIONS tree, where RegisterRoutes
+// mounts every path once; HEAD routes are GET's paths, so checking GET
+// routes covers them.
+func findRouteConflict(a, b RouteInfo) string {
+	// Each version is mounted under its own prefix
+	if a.Version != b.Version {
+		return ""
+	}
+	if a.Method == b.Method {
+		return findPathConflict(a.Path, b.Path)
+	}
+
+	reason := findPathConflict(a.Path, b.Path)
+	if reason == "" || reason == "duplicate route" {
+		// A path is mounted for OPTIONS only once
+		return ""
+	}
+	return reason + " in the OPTIONS routes"
+}
+
+// findPathConflict returns why two paths can't share a radix tree, or an
+// empty string. The rules mirror gin's: static and param segments may share
+// a position, but param names must agree and catch-alls must stand alone.
+func findPathConflict(pathA, pathB string) string {
+	segmentsA := splitPath(pathA)
+	segmentsB := splitPath(pathB)
+
+	for i := 0; i < len(segmentsA) && i < len(segmentsB); i++ {
+		segA, segB := segmentsA[i], segmentsB[i]
+		kindA, kindB := segmentKind(segA), segmentKind(segB)
+
+		switch {
+		case kindA == '*' || kindB == '*':
+			if segA == segB && len(segmentsA) == len(segmentsB) {
+				return "duplicate route"
+			}
+			return fmt.Sprintf("overlapping wildcards '%s' and '%s'", segA, segB)
+
+		case kindA == ':' && kindB == ':':
+			if segA != segB {
+				return fmt.Sprintf("conflicting path parameter names '%s' and '%s'", segA, segB)
+			}
+
+		case kindA != kindB:
+			// gin resolves static segments before params, so the paths diverge here
+			return ""
+
+		case segA != segB:
+			return ""
+		}
+	}
+
+	if len(segmentsA) == len(segmentsB) {
+		return "duplicate route"
+	}
+	return ""
+}
+
+// splitPath splits a route path into its segments
+func splitPath(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, "/")
+}
+
+// segmentKind returns ':' for params, '*' for catch-alls and 0 for static segments
+func segmentKind(segment string) byte {
+	if segment != "" && (segment[0] == ':' || segment[0] == '*') {
+		return segment[0]
+	}
+	return 0
+}
+
+// isSupportedMethod reports whether RegisterRoutes knows how to mount the method
+func isSupportedMethod(method string) bool {
+	switch method {
+	case "GET", "POST", "PUT", "DELETE", "PATCH":
+		return true
+	default:
+		return false
+	}
+}
+
+// routeOwner formats the handler method that declared a route
+func routeOwner(route RouteInfo) string {
+	return route.HandlerName + "." + route.MethodName
+}
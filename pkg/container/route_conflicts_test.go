@@ -0,0 +1,146 @@
+package container
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"study-go-controller/pkg/routing"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// thingHandler declares whatever routes it is given for its two methods
+type thingHandler struct {
+	routes []routing.RouteSpec
+}
+
+func (h *thingHandler) Routes() []routing.RouteSpec { return h.routes }
+
+func (h *thingHandler) Fetch(c *gin.Context)  { c.Status(http.StatusOK) }
+func (h *thingHandler) Remove(c *gin.Context) { c.Status(http.StatusNoContent) }
+
+func TestValidateReportsConflictingRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []routing.RouteSpec
+		reason string
+	}{
+		{"duplicate", []routing.RouteSpec{routing.GET("/:id", "Fetch"), routing.GET("/:id", "Remove")},
+			"duplicate route"},
+		{"param names", []routing.RouteSpec{routing.GET("/:id", "Fetch"), routing.GET("/:key", "Remove")},
+			"conflicting path parameter names ':id' and ':key'"},
+		{"catch-all", []routing.RouteSpec{routing.GET("/*path", "Fetch"), routing.GET("/:id", "Remove")},
+			"overlapping wildcards '*path' and ':id'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewAutoRouter()
+			if err := router.RegisterHandler("/things", &thingHandler{routes: tt.routes}); err != nil {
+				t.Fatalf("RegisterHandler: %v", err)
+			}
+
+			err := router.Validate()
+			var conflicts *RouteConflictError
+			if !errors.As(err, &conflicts) {
+				t.Fatalf("Validate() = %v, want a RouteConflictError", err)
+			}
+			if len(conflicts.Conflicts) != 1 || conflicts.Conflicts[0].Reason != tt.reason {
+				t.Fatalf("Validate() = %v, want one conflict %q", err, tt.reason)
+			}
+			if !strings.Contains(err.Error(), "thingHandler.Fetch") || !strings.Contains(err.Error(), "thingHandler.Remove") {
+				t.Errorf("Validate() = %q, want both handler methods named", err)
+			}
+		})
+	}
+}
+
+func TestValidateAcceptsRoutesGinCanMount(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []routing.RouteSpec
+	}{
+		{"static beside param", []routing.RouteSpec{routing.GET("/latest", "Fetch"), routing.GET("/:id", "Remove")}},
+		{"longer path", []routing.RouteSpec{routing.GET("/:id", "Fetch"), routing.GET("/:id/parts", "Remove")}},
+		{"other method", []routing.RouteSpec{routing.GET("/:id", "Fetch"), routing.DELETE("/:id", "Remove")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewAutoRouter()
+			if err := router.RegisterHandler("/things", &thingHandler{routes: tt.routes}); err != nil {
+				t.Fatalf("RegisterHandler: %v", err)
+			}
+			if err := router.Validate(); err != nil {
+				t.Fatalf("Validate() = %v, want no conflicts", err)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsParamNamesDifferingAcrossMethods(t *testing.T) {
+	tests := []struct {
+		name, getPath, deletePath, reason string
+	}{
+		{"param names", "/:id", "/:key", "conflicting path parameter names ':id' and ':key' in the OPTIONS routes"},
+		{"catch-all", "/*path", "/:id", "overlapping wildcards '*path' and ':id' in the OPTIONS routes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewAutoRouter()
+			handler := &thingHandler{routes: []routing.RouteSpec{
+				routing.GET(tt.getPath, "Fetch"),
+				routing.DELETE(tt.deletePath, "Remove"),
+			}}
+			if err := router.RegisterHandler("/things", handler); err != nil {
+				t.Fatalf("RegisterHandler: %v", err)
+			}
+
+			err := router.Validate()
+			var conflicts *RouteConflictError
+			if !errors.As(err, &conflicts) {
+				t.Fatalf("Validate() = %v, want a RouteConflictError", err)
+			}
+			if len(conflicts.Conflicts) != 1 || conflicts.Conflicts[0].Reason != tt.reason {
+				t.Fatalf("Validate() = %v, want one conflict %q", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestValidatedRoutesMountWithoutPanic(t *testing.T) {
+	router := NewAutoRouter()
+	handler := &thingHandler{routes: []routing.RouteSpec{
+		routing.GET("/:id", "Fetch"),
+		routing.DELETE("/:id", "Remove"),
+	}}
+	if err := router.RegisterHandler("/things", handler); err != nil {
+		t.Fatalf("RegisterHandler: %v", err)
+	}
+	if err := router.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	engine := gin.New()
+	router.RegisterRoutes(engine.Group(apiPrefix))
+
+	for method, status := range map[string]int{
+		http.MethodGet:     http.StatusOK,
+		http.MethodHead:    http.StatusOK,
+		http.MethodDelete:  http.StatusNoContent,
+		http.MethodOptions: http.StatusNoContent,
+	} {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(method, "/api/v1/things/7", nil))
+		if recorder.Code != status {
+			t.Errorf("%s /api/v1/things/7 = %d, want %d", method, recorder.Code, status)
+		}
+		if method == http.MethodOptions && !strings.Contains(recorder.Header().Get("Allow"), "DELETE") {
+			t.Errorf("OPTIONS Allow = %q, want it to list DELETE", recorder.Header().Get("Allow"))
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"study-go-controller/pkg/middleware"
+	"study-go-controller/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RequestIDHeader carries the request ID; one is generated when the client doesn't send it
+const RequestIDHeader = "X-Request-ID"
+
+// scopeKey stores the request scope in the gin context
+const scopeKey = "container.scope"
+
+// scopeContextKey stores the request scope in the request's context.Context
+type scopeContextKey struct{}
+
+// RequestContext describes the request a scope was created for.
+// Scoped components can take it as a constructor parameter.
+type RequestContext struct {
+	RequestID string
+	UserID    uint // verified by middleware.Authenticate; 0 for anonymous requests
+	Method    string
+	Path      string
+}
+
+// RequestScope creates a child scope of factory for every request and
+// supplies it with the RequestContext and a *gorm.DB, so scoped repositories
+// resolved for the request, see FromScope, use it:
+//
+//   - POST, PUT, PATCH and DELETE get a transaction, committed when the
+//     response is successful and rolled back on 4xx/5xx, gin errors or panics
+//   - other methods get db bound to the request's context
+//
+// Transactional responses are buffered until the commit, so clients never
+// see a success for a write that failed to commit.
+func RequestScope(factory *Factory, db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+		// Mounted after middleware.Authenticate, see Container.RegisterRoutes
+		userID, _ := middleware.CurrentUserID(c)
+
+		scope := factory.Scope()
+		scope.Supply(&RequestContext{
+			RequestID: requestID,
+			UserID:    userID,
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+		})
+		c.Set(scopeKey, scope)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), scopeContextKey{}, scope))
+
+		if db == nil {
+			c.Next()
+			return
+		}
+		if isSafeMethod(c.Request.Method) {
+			scope.Supply(db.WithContext(c.Request.Context()))
+			c.Next()
+			return
+		}
+
+		tx := db.WithContext(c.Request.Context()).Begin()
+		if tx.Error != nil {
+			log.Printf("❌ [%s] Failed to begin transaction: %v", requestID, tx.Error)
+			response.ErrorResponse(c, http.StatusInternalServerError, "Failed to begin transaction")
+			c.Abort()
+			return
+		}
+		scope.Supply(tx)
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+			if r := recover(); r != nil {
+				tx.Rollback()
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		if writer.Status() >= http.StatusBadRequest || len(c.Errors) > 0 {
+			if err := tx.Rollback().Error; err != nil {
+				log.Printf("❌ [%s] Rollback failed: %v", requestID, err)
+			}
+			writer.flush()
+			return
+		}
+		if err := tx.Commit().Error; err != nil {
+			log.Printf("❌ [%s] Commit failed: %v", requestID, err)
+			c.Writer = writer.ResponseWriter
+			response.ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+			return
+		}
+		writer.flush()
+	}
+}
+
+// ScopeFrom returns the request scope created by RequestScope, or nil
+func ScopeFrom(c *gin.Context) *Factory {
+	scope, ok := c.Get(scopeKey)
+	if !ok {
+		return nil
+	}
+	factory, _ := scope.(*Factory)
+	return factory
+}
+
+// ScopeFromContext returns the request scope of ctx, a *gin.Context or its
+// request's context, or nil
+func ScopeFromContext(ctx context.Context) *Factory {
+	if c, ok := ctx.(*gin.Context); ok {
+		return ScopeFrom(c)
+	}
+	factory, _ := ctx.Value(scopeContextKey{}).(*Factory)
+	return factory
+}
+
+// FromScope resolves T from the request scope of ctx, returning fallback
+// outside a request. Singleton services use it to reach the request's
+// scoped repositories:
+//
+//	func (s *userService) WithContext(ctx context.Context) UserService {
+//		return &userService{userRepo: container.FromScope(ctx, s.userRepo)}
+//	}
+//
+// T is provided when the scope exists, so failing to resolve it is a wiring
+// bug and panics.
+func FromScope[T any](ctx context.Context, fallback T) T {
+	scope := ScopeFromContext(ctx)
+	if scope == nil {
+		return fallback
+	}
+	instance, err := scope.Get(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		panic(fmt.Sprintf("container: resolving from the request scope: %v", err))
+	}
+	return instance.(T)
+}
+
+// isSafeMethod reports whether a method doesn't modify resources
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// newRequestID returns a random 16-byte hex ID
+func newRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// bufferedWriter holds the response body back until flush. The status is
+// recorded by the wrapped writer, which doesn't send it before the body.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// WriteHeaderNow is deferred to flush
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+// flush sends the status and buffered body
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeaderNow()
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
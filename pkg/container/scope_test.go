@@ -0,0 +1,51 @@
+package container
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"study-go-controller/pkg/auth"
+	"study-go-controller/pkg/middleware"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestContextTakesTheAuthenticatedUser(t *testing.T) {
+	tokens, err := auth.NewTokens(auth.RandomSecret(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _, _ := tokens.Issue(7)
+
+	router := gin.New()
+	router.Use(middleware.Authenticate(tokens), RequestScope(NewFactory(), nil))
+	router.GET("/whoami", func(c *gin.Context) {
+		requestContext, err := ScopeFrom(c).Get(reflect.TypeOf(&RequestContext{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.JSON(http.StatusOK, requestContext.(*RequestContext).UserID)
+	})
+
+	tests := map[string]struct {
+		header, value string
+		want          string
+	}{
+		"token":           {"Authorization", "Bearer " + token, "7"},
+		"claimed user ID": {"X-User-ID", "7", "0"},
+		"anonymous":       {"", "", "0"},
+	}
+	for name, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Body.String() != tt.want {
+			t.Errorf("%s: RequestContext.UserID = %s, want %s", name, recorder.Body, tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"study-go-controller/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"gorm.io/gorm"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// typedSignature inspects a method shaped like
+//
+//	func(ctx context.Context[, req *Request]) ([*Response, ]error)
+//
+// and returns its request and response types (nil when absent)
+func typedSignature(methodType reflect.Type) (request, response reflect.Type, ok bool) {
+	// In(0) is the receiver
+	in, out := methodType.NumIn(), methodType.NumOut()
+	if in < 2 || in > 3 || methodType.In(1) != contextType {
+		return nil, nil, false
+	}
+	if in == 3 {
+		request = methodType.In(2)
+		if request.Kind() != reflect.Ptr || request.Elem().Kind() != reflect.Struct {
+			return nil, nil, false
+		}
+	}
+
+	if out < 1 || out > 2 || methodType.Out(out-1) != errorType {
+		return nil, nil, false
+	}
+	if out == 2 {
+		response = methodType.Out(0)
+	}
+	return request, response, true
+}
+
+// typedCall binds the request struct from path, query and body, validates
+// it, calls the typed method and renders the result through pkg/response
+func typedCall(method reflect.Method, route RouteInfo) routeCall {
+	request, responseType, _ := typedSignature(method.Type)
+	return renderTyped(route, reflectRequest(request), func(handler interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+		args := []reflect.Value{reflect.ValueOf(handler), reflect.ValueOf(ctx)}
+		if req != nil {
+			args = append(args, reflect.ValueOf(req))
+		}
+		results := method.Func.Call(args)
+
+		if errValue := results[len(results)-1]; !errValue.IsNil() {
+			return nil, errValue.Interface().(error)
+		}
+		if responseType != nil {
+			return results[0].Interface(), nil
+		}
+		return nil, nil
+	})
+}
+
+// reflectRequest allocates requests of type request, a struct pointer;
+// nil for methods without a request
+func reflectRequest(request reflect.Type) func() interface{} {
+	if request == nil {
+		return nil
+	}
+	return func() interface{} { return reflect.New(request.Elem()).Interface() }
+}
+
+// renderTyped wraps invoke, a typed method call, with request binding and
+// response rendering with the route's success status; newRequest is nil for
+// methods without a request
+func renderTyped(route RouteInfo, newRequest func() interface{}, invoke func(handler interface{}, ctx context.Context, req interface{}) (interface{}, error)) routeCall {
+	successStatus := route.SuccessStatus
+	if successStatus == 0 {
+		successStatus = defaultSuccessStatus(route.Method, route.MethodName)
+	}
+	message := route.SuccessMessage
+	if message == "" {
+		message = http.StatusText(successStatus)
+	}
+
+	return func(handler interface{}, c *gin.Context) {
+		var req interface{}
+		if newRequest != nil {
+			req = newRequest()
+			if err := bindRequest(c, req); err != nil {
+				response.ValidationErrorResponse(c, err)
+				return
+			}
+		}
+
+		data, err := invoke(handler, c, req)
+		if err != nil {
+			response.FromError(c, mapServiceError(err))
+			return
+		}
+		response.SuccessResponse(c, successStatus, message, data)
+	}
+}
+
+// bindRequest fills req from the query string, the JSON body and the path
+// params (in that order, so path params always win) and validates it once
+func bindRequest(c *gin.Context, req interface{}) error {
+	if err := binding.MapFormWithTag(req, c.Request.URL.Query(), "form"); err != nil {
+		return err
+	}
+
+	if c.Request.Body != nil && c.Request.ContentLength != 0 {
+		if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+
+	params := make(map[string][]string, len(c.Params))
+	for _, param := range c.Params {
+		params[param.Key] = []string{param.Value}
+	}
+	if err := binding.MapFormWithTag(req, params, "uri"); err != nil {
+		return err
+	}
+
+	return binding.Validator.ValidateStruct(req)
+}
+
+// mapServiceError translates well-known persistence errors into HTTP errors.
+// Errors that already carry a status are left untouched.
+func mapServiceError(err error) error {
+	var httpErr *response.HTTPError
+	if errors.As(err, &httpErr) {
+		return err
+	}
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return response.WrapError(http.StatusNotFound, "Resource not found", err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return response.WrapError(http.StatusConflict, "Resource already exists", err)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return response.WrapError(http.StatusBadRequest, "Referenced resource does not exist", err)
+	}
+	return err
+}
@@ -0,0 +1,140 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"study-go-controller/pkg/response"
+	"study-go-controller/pkg/routing"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// searchRequest takes its fields from every source bindRequest reads
+type searchRequest struct {
+	ID    uint   `form:"id" json:"id" uri:"id" binding:"required"`
+	Name  string `form:"name" json:"name"`
+	Limit int    `form:"limit" json:"limit" binding:"omitempty,max=100"`
+}
+
+// bindContext builds a context for a request with query, JSON body and path id
+func bindContext(query, body, id string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/search?"+query, strings.NewReader(body))
+	if id != "" {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+	}
+	return c
+}
+
+func TestBindRequestPrecedence(t *testing.T) {
+	tests := []struct {
+		name            string
+		query, body, id string
+		want            searchRequest
+		wantErr         bool
+	}{
+		{"query only", "id=1&name=q&limit=5", "", "", searchRequest{ID: 1, Name: "q", Limit: 5}, false},
+		{"body overrides query", "id=1&name=q&limit=5", `{"id": 2, "name": "b"}`, "", searchRequest{ID: 2, Name: "b", Limit: 5}, false},
+		{"path overrides body", "id=1", `{"id": 2, "name": "b"}`, "3", searchRequest{ID: 3, Name: "b"}, false},
+		{"path overrides query", "id=1&name=q", "", "3", searchRequest{ID: 3, Name: "q"}, false},
+		{"missing required field", "name=q", "", "", searchRequest{}, true},
+		{"validated after merging", "id=1", `{"limit": 500}`, "", searchRequest{}, true},
+		{"malformed body", "id=1", `{"name": `, "", searchRequest{}, true},
+		{"malformed path param", "", "", "seven", searchRequest{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req searchRequest
+			err := bindRequest(bindContext(tt.query, tt.body, tt.id), &req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("bindRequest() = %+v, want an error", req)
+				}
+				return
+			}
+			if err != nil || req != tt.want {
+				t.Fatalf("bindRequest() = %+v, %v, want %+v", req, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapServiceError(t *testing.T) {
+	declared := response.NewError(http.StatusForbidden, "Not yours")
+	plain := errors.New("boom")
+	tests := []struct {
+		name   string
+		err    error
+		status int // 0 for errors passed through without a status
+	}{
+		{"not found", gorm.ErrRecordNotFound, http.StatusNotFound},
+		{"wrapped not found", fmt.Errorf("loading post: %w", gorm.ErrRecordNotFound), http.StatusNotFound},
+		{"duplicate", gorm.ErrDuplicatedKey, http.StatusConflict},
+		{"foreign key", gorm.ErrForeignKeyViolated, http.StatusBadRequest},
+		{"declared status", declared, http.StatusForbidden},
+		{"declared status wrapping not found", response.WrapError(http.StatusGone, "Gone", gorm.ErrRecordNotFound), http.StatusGone},
+		{"plain", plain, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapped := mapServiceError(tt.err)
+			var httpErr *response.HTTPError
+			if !errors.As(mapped, &httpErr) {
+				if tt.status != 0 {
+					t.Fatalf("mapServiceError() = %v, want status %d", mapped, tt.status)
+				}
+				if mapped != tt.err {
+					t.Fatalf("mapServiceError() = %v, want the error unchanged", mapped)
+				}
+				return
+			}
+			if httpErr.Status != tt.status {
+				t.Fatalf("mapServiceError() status = %d, want %d", httpErr.Status, tt.status)
+			}
+			if !errors.Is(mapped, tt.err) {
+				t.Errorf("mapServiceError() = %v, lost the original error", mapped)
+			}
+		})
+	}
+}
+
+// typedStatusHandler has typed POST routes with and without declared statuses
+type typedStatusHandler struct{}
+
+func (h *typedStatusHandler) Routes() []routing.RouteSpec {
+	return []routing.RouteSpec{
+		routing.POST("/:id/archive", "ArchiveWidget"),
+		routing.POST("/import", "ImportWidgets").WithStatus(http.StatusAccepted),
+	}
+}
+
+func (h *typedStatusHandler) CreateWidget(ctx context.Context) (*widgetBody, error) {
+	return &widgetBody{}, nil
+}
+func (h *typedStatusHandler) ArchiveWidget(ctx context.Context) (*widgetBody, error) {
+	return &widgetBody{}, nil
+}
+func (h *typedStatusHandler) ImportWidgets(ctx context.Context) (*widgetBody, error) {
+	return &widgetBody{}, nil
+}
+
+func TestTypedRoutesUseTheirSuccessStatus(t *testing.T) {
+	routes := itemRoutes(t, &typedStatusHandler{})
+	for method, want := range map[string]int{
+		"CreateWidget":  http.StatusCreated,
+		"ArchiveWidget": http.StatusOK,
+		"ImportWidgets": http.StatusAccepted,
+	} {
+		c, recorder := itemContext()
+		routes[method](c)
+		if recorder.Code != want {
+			t.Errorf("%s = %d, want %d", method, recorder.Code, want)
+		}
+	}
+}
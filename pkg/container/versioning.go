@@ -0,0 +1,180 @@
+package container
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"study-go-controller/pkg/routing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultVersion is the API version handlers are registered in unless told otherwise
+const defaultVersion = "v1"
+
+// APIVersion describes an API version mounted under /api/<Name>
+type APIVersion struct {
+	Name string
+	// Base is the version whose routes are inherited; empty starts from scratch
+	Base string
+	// Deprecation applies to every route served by this version
+	Deprecation *routing.Deprecation
+}
+
+// routeRemoval drops an inherited route from a version
+type routeRemoval struct {
+	version     string
+	method      string
+	path        string
+	handlerName string
+}
+
+// AddVersion registers an API version. Its base version must already exist.
+func (ar *AutoRouter) AddVersion(version APIVersion) error {
+	if ar.hasVersion(version.Name) {
+		return fmt.Errorf("API version %s is already registered", version.Name)
+	}
+	if version.Base != "" && !ar.hasVersion(version.Base) {
+		return fmt.Errorf("API version %s inherits unknown version %s", version.Name, version.Base)
+	}
+	ar.versions = append(ar.versions, version)
+	return nil
+}
+
+// Versions returns the registered API versions in registration order
+func (ar *AutoRouter) Versions() []APIVersion {
+	return ar.versions
+}
+
+// hasVersion reports whether an API version is registered
+func (ar *AutoRouter) hasVersion(name string) bool {
+	for _, version := range ar.versions {
+		if version.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRoutes computes the effective route table of every version.
+// A version starts with its base version's routes, drops removed ones,
+// and replaces inherited routes that it redeclares with the same method and path.
+func (ar *AutoRouter) resolveRoutes() []RouteInfo {
+	// Routes per version before version-wide deprecation is applied,
+	// so a deprecated base doesn't leak its notice into newer versions
+	declared := make(map[string][]RouteInfo)
+	var all []RouteInfo
+
+	for _, version := range ar.versions {
+		var own []RouteInfo
+		for _, route := range ar.routes {
+			if route.Version == version.Name {
+				own = append(own, route)
+			}
+		}
+
+		var routes []RouteInfo
+		for _, route := range declared[version.Base] {
+			if ar.isRemoved(version.Name, route) || containsRoute(own, route.Method, route.Path) {
+				continue
+			}
+			route.Version = version.Name
+			routes = append(routes, route)
+		}
+		routes = append(routes, own...)
+		declared[version.Name] = routes
+
+		for _, route := range routes {
+			if route.Deprecation == nil {
+				route.Deprecation = version.Deprecation
+			}
+			all = append(all, route)
+		}
+	}
+
+	return all
+}
+
+// validateVersions checks that routes and removals refer to known versions and routes
+func (ar *AutoRouter) validateVersions() error {
+	for _, route := range ar.routes {
+		if !ar.hasVersion(route.Version) {
+			return fmt.Errorf("%s declares %s %s in unknown API version %s",
+				routeOwner(route), route.Method, route.Path, route.Version)
+		}
+	}
+
+	resolved := ar.resolveRoutes()
+	for _, removal := range ar.removals {
+		base := ""
+		for _, version := range ar.versions {
+			if version.Name == removal.version {
+				base = version.Base
+			}
+		}
+		if base == "" {
+			return fmt.Errorf("%s removes %s %s but API version %s has no base version",
+				removal.handlerName, removal.method, removal.path, removal.version)
+		}
+
+		found := false
+		for _, route := range resolved {
+			if route.Version == base && route.Method == removal.method && route.Path == removal.path {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s removes %s %s which API version %s doesn't serve",
+				removal.handlerName, removal.method, removal.path, base)
+		}
+	}
+	return nil
+}
+
+// isRemoved reports whether a route is removed from a version
+func (ar *AutoRouter) isRemoved(version string, route RouteInfo) bool {
+	for _, removal := range ar.removals {
+		if removal.version == version && removal.method == route.Method && removal.path == route.Path {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRoute reports whether routes already has the method and path
+func containsRoute(routes []RouteInfo, method, path string) bool {
+	for _, route := range routes {
+		if route.Method == method && route.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// deprecationHeaders announces a deprecated route (RFC 9745 / RFC 8594)
+func deprecationHeaders(deprecation *routing.Deprecation) gin.HandlerFunc {
+	value := "true"
+	if !deprecation.Since.IsZero() {
+		value = "@" + strconv.FormatInt(deprecation.Since.Unix(), 10)
+	}
+
+	var links []string
+	if deprecation.Link != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="deprecation"`, deprecation.Link))
+	}
+	if deprecation.Successor != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="successor-version"`, deprecation.Successor))
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", value)
+		if !deprecation.Sunset.IsZero() {
+			c.Header("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if len(links) > 0 {
+			c.Header("Link", strings.Join(links, ", "))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"study-go-controller/pkg/config"
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestMySQLDSNEscapesCredentials(t *testing.T) {
+	cfg := config.DatabaseConfig{User: "app", Password: "p@ss:w/rd?", Host: "db.internal", Port: "3306", Name: "app"}
+
+	parsed, err := mysqldriver.ParseDSN(mysqlDSN(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.User != cfg.User || parsed.Passwd != cfg.Password || parsed.Addr != "db.internal:3306" ||
+		parsed.DBName != cfg.Name || !parsed.ParseTime || parsed.Loc != time.Local ||
+		parsed.Params["charset"] != "utf8mb4" {
+		t.Errorf("DSN parsed back to %+v", parsed)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		err    error
+		want   bool
+	}{
+		{"connection refused", DriverMySQL, errors.New("dial tcp 127.0.0.1:3306: connect: connection refused"), true},
+		{"mysql too many connections", DriverMySQL, &mysqldriver.MySQLError{Number: 1040}, true},
+		{"mysql access denied", DriverMySQL, fmt.Errorf("ping: %w", &mysqldriver.MySQLError{Number: 1045}), false},
+		{"mysql unknown database", DriverMySQL, &mysqldriver.MySQLError{Number: 1049}, false},
+		{"postgres starting up", DriverPostgres, &pgconn.PgError{Code: "57P03"}, true},
+		{"postgres wrong password", DriverPostgres, &pgconn.PgError{Code: "28P01"}, false},
+		{"postgres unknown database", DriverPostgres, &pgconn.PgError{Code: "3D000"}, false},
+		{"postgres invalid dsn", DriverPostgres, &pgconn.ParseConfigError{}, false},
+		{"unsupported driver", "oracle", fmt.Errorf("%w %q", errUnsupportedDriver, "oracle"), false},
+		{"sqlite", DriverSQLite, errors.New("unable to open database file"), false},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.driver, tt.err); got != tt.want {
+			t.Errorf("%s: isTransient = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSQLiteEnforcesForeignKeys(t *testing.T) {
+	db, err := NewDatabase(config.DatabaseConfig{Driver: DriverSQLite, Name: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		"CREATE TABLE authors (id INTEGER PRIMARY KEY)",
+		"CREATE TABLE books (id INTEGER PRIMARY KEY, author_id INTEGER REFERENCES authors(id))",
+	}
+	for _, statement := range statements {
+		if err := db.DB.Exec(statement).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = db.DB.Exec("INSERT INTO books (id, author_id) VALUES (1, 42)").Error
+	if !errors.Is(err, gorm.ErrForeignKeyViolated) {
+		t.Fatalf("insert with an unknown author = %v, want %v", err, gorm.ErrForeignKeyViolated)
+	}
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"study-go-controller/pkg/metrics"
+	"time"
+)
+
+// CheckHealth pings the database, so /ready reports a lost connection
+func (d *Database) CheckHealth(ctx context.Context) error {
+	sqlDB, err := d.sqlDB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// CheckReady pings the database and, when it is a replica, fails while it
+// lags more than MaxReplicationLag behind its primary
+func (d *Database) CheckReady(ctx context.Context) error {
+	if err := d.CheckHealth(ctx); err != nil {
+		return err
+	}
+	if d.MaxReplicationLag <= 0 {
+		return nil
+	}
+
+	lag, replicating, err := d.ReplicationLag(ctx)
+	if err != nil {
+		return fmt.Errorf("checking replication lag: %w", err)
+	}
+	if replicating && lag > d.MaxReplicationLag {
+		return fmt.Errorf("replication lag %s exceeds %s", lag, d.MaxReplicationLag)
+	}
+	return nil
+}
+
+// ReplicationLag returns how far the server is behind its primary;
+// replicating is false for a primary and for SQLite
+func (d *Database) ReplicationLag(ctx context.Context) (lag time.Duration, replicating bool, err error) {
+	db := d.DB.WithContext(ctx)
+	switch db.Dialector.Name() {
+	case DriverPostgres:
+		// An idle primary replays nothing, so a caught-up replica counts as no lag
+		var seconds sql.NullFloat64
+		err := db.Raw(`SELECT CASE
+			WHEN NOT pg_is_in_recovery() THEN NULL
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+		END`).Scan(&seconds).Error
+		if err != nil || !seconds.Valid {
+			return 0, false, err
+		}
+		return time.Duration(seconds.Float64 * float64(time.Second)), true, nil
+
+	case DriverMySQL:
+		return mysqlReplicationLag(ctx, d)
+	}
+	return 0, false, nil
+}
+
+// mysqlReplicationLag reads SHOW REPLICA STATUS, or SHOW SLAVE STATUS
+// before MySQL 8.0.22
+func mysqlReplicationLag(ctx context.Context, d *Database) (time.Duration, bool, error) {
+	db := d.DB.WithContext(ctx)
+	var status map[string]interface{}
+	column := "Seconds_Behind_Source"
+	if err := db.Raw("SHOW REPLICA STATUS").Scan(&status).Error; err != nil {
+		column = "Seconds_Behind_Master"
+		if err := db.Raw("SHOW SLAVE STATUS").Scan(&status).Error; err != nil {
+			return 0, false, err
+		}
+	}
+	if len(status) == 0 {
+		return 0, false, nil
+	}
+
+	// NULL while the replication threads are stopped
+	var seconds string
+	switch value := status[column].(type) {
+	case nil:
+		return 0, true, fmt.Errorf("replication is not running")
+	case []byte:
+		seconds = string(value)
+	default:
+		seconds = fmt.Sprint(value)
+	}
+	lag, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return 0, true, fmt.Errorf("unexpected %s %q", column, seconds)
+	}
+	return time.Duration(lag) * time.Second, true, nil
+}
+
+// Metrics reports the connection pool, e.g. db_pool_in_use{db="primary"}
+func (d *Database) Metrics() []metrics.Metric {
+	sqlDB, err := d.sqlDB()
+	if err != nil {
+		return nil
+	}
+	stats := sqlDB.Stats()
+	labels := map[string]string{"db": d.Role}
+	sample := func(name, help, kind string, value float64) metrics.Metric {
+		return metrics.Metric{Name: name, Help: help, Type: kind, Labels: labels, Value: value}
+	}
+
+	return []metrics.Metric{
+		sample("db_pool_max_open", "Maximum number of open connections.", metrics.Gauge, float64(stats.MaxOpenConnections)),
+		sample("db_pool_open", "Established connections, in use or idle.", metrics.Gauge, float64(stats.OpenConnections)),
+		sample("db_pool_in_use", "Connections currently in use.", metrics.Gauge, float64(stats.InUse)),
+		sample("db_pool_idle", "Idle connections.", metrics.Gauge, float64(stats.Idle)),
+		sample("db_pool_wait_count_total", "Connections waited for.", metrics.Counter, float64(stats.WaitCount)),
+		sample("db_pool_wait_duration_seconds_total", "Time spent waiting for connections.", metrics.Counter, stats.WaitDuration.Seconds()),
+		sample("db_pool_max_idle_closed_total", "Connections closed by the idle limit.", metrics.Counter, float64(stats.MaxIdleClosed)),
+		sample("db_pool_max_idle_time_closed_total", "Connections closed by the idle time limit.", metrics.Counter, float64(stats.MaxIdleTimeClosed)),
+		sample("db_pool_max_lifetime_closed_total", "Connections closed by the lifetime limit.", metrics.Counter, float64(stats.MaxLifetimeClosed)),
+	}
+}
+
+// sqlDB returns the underlying pool
+func (d *Database) sqlDB() (*sql.DB, error) {
+	if d.DB == nil {
+		return nil, fmt.Errorf("database is not connected")
+	}
+	return d.DB.DB()
+}
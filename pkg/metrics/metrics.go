@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Types of a metric in the Prometheus text format
+const (
+	Gauge   = "gauge"
+	Counter = "counter"
+)
+
+// Metric is one sample, e.g. db_pool_in_use{db="primary"} 3
+type Metric struct {
+	Name   string
+	Help   string
+	Type   string // Gauge or Counter
+	Labels map[string]string
+	Value  float64
+}
+
+// Reporter is implemented by components that expose metrics. The container
+// serves every registered reporter at /metrics and in /health.
+type Reporter interface {
+	Metrics() []Metric
+}
+
+// Write renders samples in the Prometheus text exposition format, with
+// samples of the same name grouped under one HELP and TYPE line
+func Write(w io.Writer, samples []Metric) error {
+	sorted := append([]Metric(nil), samples...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var b strings.Builder
+	for i, sample := range sorted {
+		if i == 0 || sorted[i-1].Name != sample.Name {
+			fmt.Fprintf(&b, "# HELP %s %s\n", sample.Name, sample.Help)
+			fmt.Fprintf(&b, "# TYPE %s %s\n", sample.Name, sample.Type)
+		}
+		fmt.Fprintf(&b, "%s%s %s\n", sample.Name, formatLabels(sample.Labels),
+			strconv.FormatFloat(sample.Value, 'g', -1, 64))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatLabels renders labels sorted by name, e.g. {db="primary"}
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=%s", name, strconv.Quote(labels[name]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
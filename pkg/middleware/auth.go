@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"study-go-controller/pkg/auth"
+	"study-go-controller/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userIDKey stores the authenticated user's ID in the gin context
+const userIDKey = "auth.userID"
+
+// Authenticate identifies the caller from an "Authorization: Bearer <token>"
+// header. Requests without one continue anonymously; invalid or expired
+// tokens are rejected with 401.
+func Authenticate(tokens *auth.Tokens) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		scheme, token, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			unauthorized(c, "Unsupported authorization scheme")
+			return
+		}
+		userID, err := tokens.Verify(strings.TrimSpace(token))
+		if err != nil {
+			unauthorized(c, "Invalid or expired token")
+			return
+		}
+
+		c.Set(userIDKey, userID)
+		c.Next()
+	}
+}
+
+// RequireUser rejects requests without an authenticated user.
+// It relies on Authenticate, which the container mounts for every API route.
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := CurrentUserID(c); !ok {
+			unauthorized(c, "Authentication required")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelf only lets users act on their own account: the path param
+// named param, e.g. "id" on /users/:id, must be the authenticated user's ID
+func RequireSelf(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := CurrentUserID(c)
+		if !ok {
+			unauthorized(c, "Authentication required")
+			return
+		}
+		if target, err := strconv.ParseUint(c.Param(param), 10, 32); err != nil || uint(target) != userID {
+			response.ErrorResponse(c, http.StatusForbidden, "Users can only change their own account")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentUserID returns the user ID set by Authenticate
+func CurrentUserID(c *gin.Context) (uint, bool) {
+	userID, ok := c.Get(userIDKey)
+	if !ok {
+		return 0, false
+	}
+	id, ok := userID.(uint)
+	return id, ok
+}
+
+// unauthorized aborts with 401 and asks for a bearer token
+func unauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", "Bearer")
+	response.ErrorResponse(c, http.StatusUnauthorized, message)
+	c.Abort()
+}
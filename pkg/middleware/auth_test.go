@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"study-go-controller/pkg/auth"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuthenticateAndGuards(t *testing.T) {
+	tokens, err := auth.NewTokens([]byte("0123456789abcdef0123456789abcdef"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, _, _ := tokens.Issue(7)
+
+	router := gin.New()
+	router.Use(Authenticate(tokens))
+	whoami := func(c *gin.Context) {
+		userID, _ := CurrentUserID(c)
+		c.String(http.StatusOK, "%d", userID)
+	}
+	router.GET("/public", whoami)
+	router.GET("/private", RequireUser(), whoami)
+	router.PUT("/users/:id", RequireSelf("id"), whoami)
+
+	tests := []struct {
+		name          string
+		method, path  string
+		headers       map[string]string
+		status        int
+		body          string
+		authChallenge bool
+	}{
+		{"anonymous public", http.MethodGet, "/public", nil, http.StatusOK, "0", false},
+		{"token on public", http.MethodGet, "/public", map[string]string{"Authorization": "Bearer " + alice}, http.StatusOK, "7", false},
+		{"anonymous private", http.MethodGet, "/private", nil, http.StatusUnauthorized, "", true},
+		{"token on private", http.MethodGet, "/private", map[string]string{"Authorization": "Bearer " + alice}, http.StatusOK, "7", false},
+		{"user ID header is ignored", http.MethodGet, "/private", map[string]string{"X-User-ID": "7"}, http.StatusUnauthorized, "", true},
+		{"invalid token", http.MethodGet, "/public", map[string]string{"Authorization": "Bearer " + alice + "x"}, http.StatusUnauthorized, "", true},
+		{"basic auth", http.MethodGet, "/public", map[string]string{"Authorization": "Basic YWxpY2U6c2VjcmV0"}, http.StatusUnauthorized, "", true},
+		{"own account", http.MethodPut, "/users/7", map[string]string{"Authorization": "Bearer " + alice}, http.StatusOK, "7", false},
+		{"other account", http.MethodPut, "/users/8", map[string]string{"Authorization": "Bearer " + alice}, http.StatusForbidden, "", false},
+		{"anonymous account", http.MethodPut, "/users/7", nil, http.StatusUnauthorized, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.status, recorder.Body)
+			}
+			if tt.body != "" && recorder.Body.String() != tt.body {
+				t.Errorf("user ID = %s, want %s", recorder.Body, tt.body)
+			}
+			if challenge := recorder.Header().Get("WWW-Authenticate"); (challenge != "") != tt.authChallenge {
+				t.Errorf("WWW-Authenticate = %q", challenge)
+			}
+		})
+	}
+}
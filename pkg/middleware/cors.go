@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"study-go-controller/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS adds the Access-Control-Allow-Origin family of headers to requests
+// from allowed origins. Preflight requests are answered by Preflight.
+// The "*" origin is answered as such and never with credentials, even if
+// cfg didn't pass config.CORSConfig.Validate.
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if !cfg.Enabled() || origin == "" {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		if allowsOrigin(cfg, origin) {
+			if cfg.AllowsAnyOrigin() {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+				if cfg.AllowCredentials {
+					header.Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+			if len(cfg.ExposedHeaders) > 0 && !isPreflight(c) {
+				header.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+			}
+		}
+		c.Next()
+	}
+}
+
+// Preflight answers OPTIONS for a path served with methods: Allow lists them,
+// and CORS preflights from allowed origins get the permitted methods and headers
+func Preflight(cfg config.CORSConfig, methods []string) gin.HandlerFunc {
+	allow := strings.Join(methods, ", ")
+
+	var corsMethods []string
+	for _, method := range methods {
+		if method == http.MethodOptions || containsFold(cfg.AllowedMethods, method) {
+			corsMethods = append(corsMethods, method)
+		}
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("Allow", allow)
+
+		if cfg.Enabled() && isPreflight(c) && allowsOrigin(cfg, c.GetHeader("Origin")) {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", strings.Join(corsMethods, ", "))
+			if containsFold(cfg.AllowedHeaders, "*") {
+				if requested := c.GetHeader("Access-Control-Request-Headers"); requested != "" {
+					header.Set("Access-Control-Allow-Headers", requested)
+				}
+			} else if len(cfg.AllowedHeaders) > 0 {
+				header.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+			}
+			if cfg.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+			}
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// allowsOrigin matches an origin against cfg's exact and wildcard entries
+func allowsOrigin(cfg config.CORSConfig, origin string) bool {
+	for _, allowed := range cfg.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		if prefix, suffix, found := strings.Cut(allowed, "*"); found &&
+			len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPreflight reports whether a request is a CORS preflight
+func isPreflight(c *gin.Context) bool {
+	return c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"study-go-controller/pkg/response"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBatchBodyBytes caps the batch request bodies BatchItems reads
+const MaxBatchBodyBytes = 4 << 20
+
+// bucket is a token bucket for a single client
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// RateLimiter keeps a token bucket per client IP. Its handlers share the
+// buckets, so several routes can spend one budget.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter allows each client IP up to limit tokens per window,
+// refilling tokens continuously
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:     limit,
+		window:    window,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// RateLimit allows each client IP up to limit requests per window,
+// refilling tokens continuously
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := NewRateLimiter(limit, window)
+	return func(c *gin.Context) {
+		limiter.charge(c, 1)
+	}
+}
+
+// Handler charges one token per request
+func (l *RateLimiter) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l.charge(c, 1)
+	}
+}
+
+// Weighted charges weight(c) tokens per request, at least one and at most
+// the limit, so a request heavier than the limit needs a full bucket.
+// A weight function may reject the request itself by aborting it.
+func (l *RateLimiter) Weighted(weight func(c *gin.Context) int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokens := weight(c)
+		if c.IsAborted() {
+			return
+		}
+		l.charge(c, tokens)
+	}
+}
+
+// charge takes tokens from the client's bucket, or aborts with 429
+func (l *RateLimiter) charge(c *gin.Context, tokens int) {
+	if tokens < 1 {
+		tokens = 1
+	}
+	if tokens > l.limit {
+		tokens = l.limit
+	}
+
+	if !l.take(c.ClientIP(), float64(tokens)) {
+		c.Header("Retry-After", strconv.Itoa(int(l.window.Seconds()*float64(tokens)/float64(l.limit))+1))
+		response.ErrorResponse(c, http.StatusTooManyRequests, "Rate limit exceeded")
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// take removes tokens from key's bucket if it holds that many
+func (l *RateLimiter) take(key string, tokens float64) bool {
+	now := time.Now()
+	limit := float64(l.limit)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: limit, lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	// Refill for the time elapsed since the last request
+	b.tokens += now.Sub(b.lastSeen).Seconds() * limit / l.window.Seconds()
+	if b.tokens > limit {
+		b.tokens = limit
+	}
+	b.lastSeen = now
+
+	allowed := b.tokens >= tokens
+	if allowed {
+		b.tokens -= tokens
+	}
+
+	// Once per window, forget clients whose bucket has fully refilled
+	if now.Sub(l.lastSweep) > l.window {
+		for ip, other := range l.buckets {
+			if now.Sub(other.lastSeen) > l.window {
+				delete(l.buckets, ip)
+			}
+		}
+		l.lastSweep = now
+	}
+	return allowed
+}
+
+// BatchItems weighs a batch request by the length of its "items" array and
+// leaves the body for the handler to bind. Bodies over MaxBatchBodyBytes are
+// rejected with 413; a malformed body weighs one and fails later in binding.
+func BatchItems(c *gin.Context) int {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxBatchBodyBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		response.ErrorResponse(c, http.StatusRequestEntityTooLarge, "Request body too large")
+		c.Abort()
+		return 0
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 1
+	}
+	var req struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return 1
+	}
+	return len(req.Items)
+}
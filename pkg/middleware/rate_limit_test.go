@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestBatchItemsSpendTheSharedBudget(t *testing.T) {
+	limiter := NewRateLimiter(10, time.Hour)
+	router := gin.New()
+	router.POST("/one", limiter.Handler(), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.POST("/batch", limiter.Weighted(BatchItems), func(c *gin.Context) {
+		var req struct {
+			Items []string `json:"items"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusCreated, "%d", len(req.Items))
+	})
+
+	post := func(path string, items int) *httptest.ResponseRecorder {
+		body := ""
+		if items > 0 {
+			encoded, _ := json.Marshal(map[string][]string{"items": make([]string, items)})
+			body = string(encoded)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return recorder
+	}
+
+	// The handler still binds the body the limiter weighed
+	if recorder := post("/batch", 8); recorder.Code != http.StatusCreated || recorder.Body.String() != "8" {
+		t.Fatalf("batch of 8 = %d %q, want 201 with all items bound", recorder.Code, recorder.Body)
+	}
+	if recorder := post("/batch", 3); recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("batch of 3 with 2 tokens left = %d, want 429", recorder.Code)
+	}
+	for i := 0; i < 2; i++ {
+		if recorder := post("/one", 0); recorder.Code != http.StatusCreated {
+			t.Fatalf("single request %d = %d, want 201", i+1, recorder.Code)
+		}
+	}
+	if recorder := post("/one", 0); recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("single request after the budget is spent = %d, want 429", recorder.Code)
+	}
+}
+
+func TestBatchItemsRejectsOversizedBodies(t *testing.T) {
+	limiter := NewRateLimiter(2, time.Hour)
+	router := gin.New()
+	router.POST("/batch", limiter.Weighted(BatchItems), func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	post := func(body string) int {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body)))
+		return recorder.Code
+	}
+
+	huge := `{"items": ["` + strings.Repeat("x", MaxBatchBodyBytes) + `"]}`
+	if status := post(huge); status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized batch = %d, want 413", status)
+	}
+	// Rejected bodies don't spend the budget
+	if status := post(`{"items": [1, 2]}`); status != http.StatusCreated {
+		t.Fatalf("batch of 2 after an oversized one = %d, want 201", status)
+	}
+}
@@ -0,0 +1,222 @@
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is one versioned schema change
+type Migration struct {
+	// Version orders migrations; generated ones are UTC timestamps, e.g. 20261016120000
+	Version int64
+	// Name describes the change, e.g. "create_users"
+	Name string
+	// Up applies the change inside a transaction
+	Up func(tx *gorm.DB) error
+	// Down reverts Up; nil when the migration is irreversible
+	Down func(tx *gorm.DB) error
+	// Checksum is the SHA-256 of an SQL migration's files, so edits to an
+	// applied migration are detected; Go migrations have none
+	Checksum string
+}
+
+// ID renders the migration as "<version>_<name>"
+func (m Migration) ID() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// Go declares a migration written as Go functions, for changes SQL can't
+// express portably such as creating tables on every driver or backfilling data:
+//
+//	migrate.Go(20261016120000, "create_users", func(tx *gorm.DB) error {
+//		return tx.Migrator().CreateTable(&user{})
+//	}, func(tx *gorm.DB) error {
+//		return tx.Migrator().DropTable("users")
+//	})
+func Go(version int64, name string, up, down func(tx *gorm.DB) error) Migration {
+	return Migration{Version: version, Name: name, Up: up, Down: down}
+}
+
+// fileName matches "<version>_<name>.<up|down>[.<dialect>].sql"
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)(?:\.(mysql|postgres|sqlite))?\.sql$`)
+
+// sqlFile is one direction of an SQL migration
+type sqlFile struct {
+	name    string
+	content string
+	dialect string // "" for files shared by every dialect
+}
+
+// FromFS loads the SQL migrations in the root of fsys for dialect, the
+// gorm dialector name (mysql, postgres or sqlite). Files are named
+// "<version>_<name>.up.sql" and "<version>_<name>.down.sql"; a file with the
+// dialect before the extension, e.g. "<version>_<name>.down.mysql.sql",
+// replaces the shared one on that dialect. A migration without a down file
+// is irreversible.
+func FromFS(fsys fs.FS, dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	type sqlMigration struct {
+		version  int64
+		name     string
+		up, down *sqlFile
+	}
+	byVersion := make(map[int64]*sqlMigration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		if match[4] != "" && match[4] != dialect {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &sqlMigration{version: version, name: match[2]}
+			byVersion[version] = m
+		}
+		if m.name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, m.name, match[2])
+		}
+
+		file := &sqlFile{name: entry.Name(), content: string(content), dialect: match[4]}
+		slot := &m.up
+		if match[3] == "down" {
+			slot = &m.down
+		}
+		// A dialect-specific file wins over the shared one
+		if *slot == nil || (*slot).dialect == "" {
+			*slot = file
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == nil {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.version, m.name)
+		}
+		migration := Migration{
+			Version:  m.version,
+			Name:     m.name,
+			Up:       execSQL(m.up),
+			Checksum: checksum(m.up, m.down),
+		}
+		if m.down != nil {
+			migration.Down = execSQL(m.down)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// execSQL runs a file's statements one by one, since not every driver
+// accepts several statements in one Exec
+func execSQL(file *sqlFile) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for _, statement := range splitStatements(file.content) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("%s: %w", file.name, err)
+			}
+		}
+		return nil
+	}
+}
+
+// splitStatements splits SQL at semicolons ending a line, dropping
+// comment-only and empty statements
+func splitStatements(content string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		statement := strings.TrimSpace(current.String())
+		current.Reset()
+		if hasCode(statement) {
+			statements = append(statements, statement)
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(strings.TrimSpace(line), ";") {
+			flush()
+		}
+	}
+	flush()
+	return statements
+}
+
+// hasCode reports whether a statement has anything besides "--" comments
+func hasCode(statement string) bool {
+	for _, line := range strings.Split(statement, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return true
+		}
+	}
+	return false
+}
+
+// checksum hashes the files of an SQL migration
+func checksum(files ...*sqlFile) string {
+	hash := sha256.New()
+	for _, file := range files {
+		if file != nil {
+			hash.Write([]byte(path.Base(file.name)))
+			hash.Write([]byte{0})
+			hash.Write([]byte(file.content))
+		}
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Create writes empty up and down files for a new SQL migration in dir,
+// versioned with the current UTC time, and returns their paths
+func Create(dir, name string) ([]string, error) {
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return nil, fmt.Errorf("migration name %q may only contain letters, digits and underscores", name)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	version := time.Now().UTC().Format("20060102150405")
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		file := filepath.Join(dir, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+		content := fmt.Sprintf("-- %s: %s\n", direction, name)
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, file)
+	}
+	return paths, nil
+}
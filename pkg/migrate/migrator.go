@@ -0,0 +1,388 @@
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// schemaMigration is a row of schema_migrations, one per applied migration
+type schemaMigration struct {
+	Version   int64  `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255;not null"`
+	Checksum  string `gorm:"size:64"`
+	AppliedAt time.Time
+	// Dirty marks a migration that started but never finished on a
+	// database without transactional DDL
+	Dirty bool `gorm:"not null;default:false"`
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// schemaMigrationLock holds at most one row while a Migrator changes the schema
+type schemaMigrationLock struct {
+	ID       int    `gorm:"primaryKey;autoIncrement:false"`
+	LockedBy string `gorm:"size:255;not null"`
+	LockedAt time.Time
+}
+
+func (schemaMigrationLock) TableName() string { return "schema_migrations_lock" }
+
+// ErrLocked is returned when another process holds the migration lock
+// longer than LockTimeout
+var ErrLocked = errors.New("migrations are locked by another process")
+
+// Status is a migration and whether it is applied
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when an applied SQL migration's files changed since
+	Modified bool
+	// Unknown is set for applied versions no migration source declares,
+	// e.g. those of a disabled module
+	Unknown bool
+	// Dirty is set when the migration failed partway on MySQL, where DDL
+	// commits implicitly; the schema may be half applied
+	Dirty bool
+}
+
+// Migrator applies and reverts migrations, recording them in schema_migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+
+	// LockTimeout is how long to wait for another process's lock
+	LockTimeout time.Duration
+}
+
+// New creates a migrator for migrations, which may come from several
+// sources; versions must be unique
+func New(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s",
+				sorted[i].Version, sorted[i-1].Name, sorted[i].Name)
+		}
+	}
+	for _, m := range sorted {
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %s has no up function", m.ID())
+		}
+	}
+	return &Migrator{db: db, migrations: sorted, LockTimeout: 30 * time.Second}, nil
+}
+
+// Status lists every migration in version order, followed by applied
+// versions that no longer have a migration
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	db := m.db.WithContext(ctx)
+	if err := m.ensureTables(db); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.AppliedAt
+			status.Modified = migration.Checksum != "" && row.Checksum != migration.Checksum
+			status.Dirty = row.Dirty
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	var unknown []Status
+	for _, row := range applied {
+		unknown = append(unknown, Status{
+			Migration: Migration{Version: row.Version, Name: row.Name, Checksum: row.Checksum},
+			Applied:   true,
+			AppliedAt: row.AppliedAt,
+			Unknown:   true,
+			Dirty:     row.Dirty,
+		})
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Version < unknown[j].Version })
+	return append(statuses, unknown...), nil
+}
+
+// Pending returns the migrations not applied yet, in version order
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Verify fails when a migration was left dirty, or when an applied SQL
+// migration was edited afterwards; fix forward with a new migration instead
+func (m *Migrator) Verify(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.Dirty {
+			return fmt.Errorf("migration %s failed partway and left the schema dirty; repair it by hand, "+
+				"then delete its schema_migrations row to run it again or clear its dirty flag to keep it", status.ID())
+		}
+		if status.Modified {
+			return fmt.Errorf("migration %s was modified after it was applied (checksum mismatch)", status.ID())
+		}
+	}
+	return nil
+}
+
+// Up applies every pending migration in version order and returns how many
+// ran. Each migration and its schema_migrations row are committed together,
+// except on MySQL, where DDL commits implicitly: there the row is written
+// dirty before the migration runs, so one that fails partway is reported by
+// Verify instead of looking never applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(db *gorm.DB) error {
+		if err := m.Verify(ctx); err != nil {
+			return err
+		}
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range pending {
+			if err := m.apply(db, migration); err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.withLock(ctx, func(db *gorm.DB) error {
+		latest, err := m.latestApplied(ctx, steps)
+		if err != nil {
+			return err
+		}
+		for _, migration := range latest {
+			if err := m.revert(db, migration); err != nil {
+				return err
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Redo reverts the last applied migration and applies it again, for
+// iterating on a migration under development
+func (m *Migrator) Redo(ctx context.Context) error {
+	return m.withLock(ctx, func(db *gorm.DB) error {
+		latest, err := m.latestApplied(ctx, 1)
+		if err != nil {
+			return err
+		}
+		if len(latest) == 0 {
+			return errors.New("no applied migration to redo")
+		}
+		if err := m.revert(db, latest[0]); err != nil {
+			return err
+		}
+		return m.apply(db, latest[0])
+	})
+}
+
+// Unlock removes a lock left behind by a process that died mid-migration
+func (m *Migrator) Unlock(ctx context.Context) error {
+	db := m.db.WithContext(ctx)
+	if err := m.ensureTables(db); err != nil {
+		return err
+	}
+	return db.Where("id = ?", 1).Delete(&schemaMigrationLock{}).Error
+}
+
+// latestApplied returns up to n applied migrations, newest first
+func (m *Migrator) latestApplied(ctx context.Context, n int) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].Version > statuses[j].Version })
+
+	var latest []Migration
+	for _, status := range statuses {
+		if len(latest) == n {
+			break
+		}
+		if !status.Applied {
+			continue
+		}
+		if status.Unknown {
+			return nil, fmt.Errorf("cannot revert %s: no migration declares it", status.ID())
+		}
+		latest = append(latest, status.Migration)
+	}
+	return latest, nil
+}
+
+// apply runs a migration's Up and records it in one transaction
+func (m *Migrator) apply(db *gorm.DB, migration Migration) error {
+	started := time.Now()
+	record := &schemaMigration{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Checksum:  migration.Checksum,
+		AppliedAt: time.Now().UTC(),
+	}
+	if !transactionalDDL(db) {
+		record.Dirty = true
+		if err := db.Create(record).Error; err != nil {
+			return fmt.Errorf("recording %s: %w", migration.ID(), err)
+		}
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Up(tx); err != nil {
+			return err
+		}
+		if record.Dirty {
+			return tx.Model(record).Updates(map[string]interface{}{"dirty": false, "applied_at": time.Now().UTC()}).Error
+		}
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		log.Printf("❌ Migration %s failed: %v", migration.ID(), err)
+		return fmt.Errorf("applying %s: %w", migration.ID(), err)
+	}
+	log.Printf("⬆️  Applied %s (%s)", migration.ID(), time.Since(started))
+	return nil
+}
+
+// revert runs a migration's Down and removes its record in one transaction
+func (m *Migrator) revert(db *gorm.DB, migration Migration) error {
+	if migration.Down == nil {
+		return fmt.Errorf("migration %s is irreversible", migration.ID())
+	}
+	started := time.Now()
+	if !transactionalDDL(db) {
+		err := db.Model(&schemaMigration{}).Where("version = ?", migration.Version).Update("dirty", true).Error
+		if err != nil {
+			return fmt.Errorf("recording %s: %w", migration.ID(), err)
+		}
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Down(tx); err != nil {
+			return err
+		}
+		return tx.Where("version = ?", migration.Version).Delete(&schemaMigration{}).Error
+	})
+	if err != nil {
+		log.Printf("❌ Reverting %s failed: %v", migration.ID(), err)
+		return fmt.Errorf("reverting %s: %w", migration.ID(), err)
+	}
+	log.Printf("⬇️  Reverted %s (%s)", migration.ID(), time.Since(started))
+	return nil
+}
+
+// withLock runs fn while holding the row in schema_migrations_lock, so two
+// processes never migrate at once
+func (m *Migrator) withLock(ctx context.Context, fn func(db *gorm.DB) error) error {
+	db := m.db.WithContext(ctx)
+	if err := m.ensureTables(db); err != nil {
+		return err
+	}
+
+	owner := lockOwner()
+	deadline := time.Now().Add(m.LockTimeout)
+	for {
+		// Inserting the single row only succeeds for one process
+		lock := schemaMigrationLock{ID: 1, LockedBy: owner, LockedAt: time.Now().UTC()}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock).Error; err != nil {
+			return err
+		}
+		var holder schemaMigrationLock
+		if err := db.First(&holder, 1).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if holder.LockedBy == owner {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w (%s since %s); run \"migrate unlock\" if it died",
+				ErrLocked, holder.LockedBy, holder.LockedAt.Format(time.RFC3339))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+	defer func() {
+		// ctx may be cancelled by now, and the lock must go regardless
+		release := m.db.WithContext(context.Background())
+		if err := release.Where("id = ? AND locked_by = ?", 1, owner).Delete(&schemaMigrationLock{}).Error; err != nil {
+			log.Printf("⚠️ Failed to release the migration lock: %v; run \"migrate unlock\"", err)
+		}
+	}()
+
+	return fn(db)
+}
+
+// transactionalDDL reports whether schema changes roll back with their
+// transaction; MySQL commits each DDL statement implicitly
+func transactionalDDL(db *gorm.DB) bool {
+	return db.Dialector.Name() != "mysql"
+}
+
+// ensureTables creates schema_migrations and its lock table
+func (m *Migrator) ensureTables(db *gorm.DB) error {
+	return db.AutoMigrate(&schemaMigration{}, &schemaMigrationLock{})
+}
+
+// applied returns the schema_migrations rows by version
+func (m *Migrator) applied(db *gorm.DB) (map[int64]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// lockOwner identifies this process in the lock table, e.g. "web-1:4242:9f86d081"
+func lockOwner() string {
+	host, _ := os.Hostname()
+	nonce := make([]byte, 4)
+	_, _ = rand.Read(nonce)
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(nonce))
+}
@@ -0,0 +1,68 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"study-go-controller/pkg/config"
+	"study-go-controller/pkg/database"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func openMigrator(t *testing.T, migrations ...Migration) (*Migrator, *gorm.DB) {
+	t.Helper()
+	db, err := database.NewDatabase(config.DatabaseConfig{Driver: database.DriverSQLite, Name: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := New(db.DB, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrator, db.DB
+}
+
+func TestLockIsReleasedAfterTheContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	migrator, db := openMigrator(t, Go(1, "cancel", func(*gorm.DB) error {
+		cancel()
+		return errors.New("interrupted")
+	}, nil))
+
+	if _, err := migrator.Up(ctx); err == nil {
+		t.Fatal("Up succeeded, want the migration's error")
+	}
+	var locks int64
+	if err := db.Model(&schemaMigrationLock{}).Count(&locks).Error; err != nil {
+		t.Fatal(err)
+	}
+	if locks != 0 {
+		t.Fatalf("%d locks left after a cancelled Up, want none", locks)
+	}
+}
+
+func TestVerifyRejectsDirtyMigrations(t *testing.T) {
+	noop := func(*gorm.DB) error { return nil }
+	migrator, db := openMigrator(t, Go(1, "half_applied", noop, noop))
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// What a MySQL migration failing partway leaves behind
+	if err := db.Model(&schemaMigration{}).Where("version = ?", 1).Update("dirty", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !statuses[0].Dirty {
+		t.Fatalf("status = %+v, want dirty", statuses[0])
+	}
+	if err := migrator.Verify(context.Background()); err == nil || !strings.Contains(err.Error(), "dirty") {
+		t.Fatalf("Verify = %v, want the dirty migration reported", err)
+	}
+}
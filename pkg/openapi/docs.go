@@ -0,0 +1,15 @@
+package openapi
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed docs.html
+var docsTemplate string
+
+// DocsPage renders the embedded API docs page for the given spec URL.
+// The page loads Swagger UI from the unpkg.com CDN, so it needs internet access.
+func DocsPage(title, specURL string) string {
+	return strings.NewReplacer("{{title}}", title, "{{specURL}}", specURL).Replace(docsTemplate)
+}
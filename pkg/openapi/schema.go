@@ -0,0 +1,246 @@
+package openapi
+
+import (
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaRegistry converts Go types into schemas and collects named structs
+// as reusable components
+type SchemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// NewSchemaRegistry creates an empty schema registry
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// Schemas returns all component schemas collected so far
+func (r *SchemaRegistry) Schemas() map[string]*Schema {
+	return r.schemas
+}
+
+// SchemaFor returns the schema for a Go type. Named structs are registered
+// as components and referenced by $ref.
+func (r *SchemaRegistry) SchemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.SchemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.SchemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return Ref(r.register(t))
+	default:
+		// interface{} and anything else accepts any value
+		return &Schema{}
+	}
+}
+
+// register adds a named struct to the components and returns its name
+func (r *SchemaRegistry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := r.schemas[name]; taken {
+		// Disambiguate same-named types from different packages
+		name = upperFirst(path.Base(t.PkgPath())) + name
+	}
+
+	// Reserve the name first so recursive types resolve to a $ref
+	r.names[t] = name
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.structSchema(t)
+	return name
+}
+
+// structSchema builds an object schema from exported struct fields
+func (r *SchemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		// Flatten embedded structs without an explicit JSON name
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner := r.structSchema(embedded)
+				for key, value := range inner.Properties {
+					schema.Properties[key] = value
+				}
+				schema.Required = append(schema.Required, inner.Required...)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := r.SchemaFor(field.Type)
+		if applyBindingRules(fieldSchema, field.Tag.Get("binding")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = fieldSchema
+	}
+
+	return schema
+}
+
+// jsonFieldName reads the property name from the json tag
+func jsonFieldName(field reflect.StructField) (name string, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	return strings.Split(tag, ",")[0], false
+}
+
+// applyBindingRules translates validator binding rules into schema
+// constraints and reports whether the field is required
+func applyBindingRules(schema *Schema, binding string) bool {
+	if binding == "" {
+		return false
+	}
+
+	required := false
+	for _, rule := range strings.Split(binding, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+
+		switch key {
+		case "dive":
+			// Remaining rules apply to the elements, not the field itself
+			return required
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		case "uuid":
+			schema.Format = "uuid"
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, option)
+			}
+		case "min", "gte":
+			setLowerBound(schema, value, false)
+		case "gt":
+			setLowerBound(schema, value, true)
+		case "max", "lte":
+			setUpperBound(schema, value, false)
+		case "lt":
+			setUpperBound(schema, value, true)
+		case "len":
+			setLowerBound(schema, value, false)
+			setUpperBound(schema, value, false)
+		}
+	}
+	return required
+}
+
+// setLowerBound applies a lower limit matching the schema type
+func setLowerBound(schema *Schema, value string, exclusive bool) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+	switch schema.Type {
+	case "string":
+		schema.MinLength = integer(n)
+	case "array":
+		schema.MinItems = integer(n)
+	case "integer", "number":
+		if exclusive {
+			schema.ExclusiveMinimum = float(n)
+		} else {
+			schema.Minimum = float(n)
+		}
+	}
+}
+
+// setUpperBound applies an upper limit matching the schema type
+func setUpperBound(schema *Schema, value string, exclusive bool) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+	switch schema.Type {
+	case "string":
+		schema.MaxLength = integer(n)
+	case "array":
+		schema.MaxItems = integer(n)
+	case "integer", "number":
+		if exclusive {
+			schema.ExclusiveMaximum = float(n)
+		} else {
+			schema.Maximum = float(n)
+		}
+	}
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func integer(n float64) *int {
+	v := int(n)
+	return &v
+}
+
+func float(n float64) *float64 {
+	return &n
+}
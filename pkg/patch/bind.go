@@ -0,0 +1,41 @@
+package patch
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"study-go-controller/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Bind applies the request body as a patch to target, which should hold the
+// current state of the resource, then validates the result with its binding tags.
+// Errors are *response.HTTPError: 415 for unsupported media types, 409 for a
+// failed "test" operation and 400 for anything else.
+func Bind(c *gin.Context, target interface{}) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return response.WrapError(http.StatusBadRequest, "Failed to read request body", err)
+	}
+
+	p, err := Parse(c.ContentType(), body)
+	if err == nil {
+		err = ApplyTo(p, target)
+	}
+	switch {
+	case errors.Is(err, ErrUnsupportedMediaType):
+		c.Header("Accept-Patch", AcceptPatch)
+		return response.WrapError(http.StatusUnsupportedMediaType, "Content-Type must be "+AcceptPatch, err)
+	case errors.Is(err, ErrTestFailed):
+		return response.WrapError(http.StatusConflict, err.Error(), err)
+	case err != nil:
+		return response.WrapError(http.StatusBadRequest, err.Error(), err)
+	}
+
+	if err := binding.Validator.ValidateStruct(target); err != nil {
+		return response.WrapError(http.StatusBadRequest, "Validation failed: "+err.Error(), err)
+	}
+	return nil
+}
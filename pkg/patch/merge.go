@@ -0,0 +1,44 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch is an RFC 7396 JSON Merge Patch document: object members
+// replace or (when null) remove the target's members, recursively
+type MergePatch []byte
+
+// Apply implements Patch
+func (p MergePatch) Apply(doc []byte) ([]byte, error) {
+	var target, patch interface{}
+	if err := decode(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := decode(p, &patch); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	return json.Marshal(mergeValue(target, patch))
+}
+
+// mergeValue implements the MergePatch algorithm of RFC 7396 section 2
+func mergeValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergeValue(targetObject[name], value)
+	}
+	return targetObject
+}
@@ -0,0 +1,115 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Supported patch media types
+const (
+	MergePatchType = "application/merge-patch+json" // RFC 7396
+	JSONPatchType  = "application/json-patch+json"  // RFC 6902
+)
+
+// AcceptPatch lists the supported media types for the Accept-Patch header
+var AcceptPatch = MergePatchType + ", " + JSONPatchType
+
+var (
+	// ErrUnsupportedMediaType is returned for bodies that are neither patch format
+	ErrUnsupportedMediaType = errors.New("unsupported patch media type")
+	// ErrInvalidPatch is returned for malformed patches or patches that can't be applied
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrTestFailed is returned when a JSON Patch "test" operation doesn't match
+	ErrTestFailed = errors.New("patch test failed")
+)
+
+// Patch transforms a JSON document
+type Patch interface {
+	Apply(doc []byte) ([]byte, error)
+}
+
+// Parse decodes a patch body according to its media type.
+// Plain application/json bodies are treated as merge patches.
+func Parse(contentType string, body []byte) (Patch, error) {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+	switch mediaType {
+	case MergePatchType, "application/json":
+		if !json.Valid(body) {
+			return nil, fmt.Errorf("%w: body is not valid JSON", ErrInvalidPatch)
+		}
+		return MergePatch(body), nil
+	case JSONPatchType:
+		var ops JSONPatch
+		if err := json.Unmarshal(body, &ops); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		return ops, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedMediaType, mediaType)
+	}
+}
+
+// ApplyTo applies p to target, a pointer to a struct. Members the patch
+// removes become zero values; members target doesn't declare are rejected.
+func ApplyTo(p Patch, target interface{}) error {
+	doc, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+
+	patched, err := p.Apply(doc)
+	if err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(target).Elem()
+	value.Set(reflect.Zero(value.Type()))
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return nil
+}
+
+// Changes compares two values of the same struct type and returns the
+// fields that differ, keyed by JSON name, with their values from after
+func Changes(before, after interface{}) map[string]interface{} {
+	beforeValue := reflect.Indirect(reflect.ValueOf(before))
+	afterValue := reflect.Indirect(reflect.ValueOf(after))
+	changes := make(map[string]interface{})
+
+	for i := 0; i < afterValue.NumField(); i++ {
+		field := afterValue.Type().Field(i)
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+
+		if !reflect.DeepEqual(beforeValue.Field(i).Interface(), afterValue.Field(i).Interface()) {
+			changes[name] = afterValue.Field(i).Interface()
+		}
+	}
+	return changes
+}
+
+// jsonName returns the JSON member name of an exported field, or "" if it is skipped
+func jsonName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,216 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// assertJSON fails unless got and want hold the same JSON value
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var a, b interface{}
+	if err := decode(got, &a); err != nil {
+		t.Fatalf("result %s: %v", got, err)
+	}
+	if err := decode([]byte(want), &b); err != nil {
+		t.Fatalf("expectation %s: %v", want, err)
+	}
+	if !equal(a, b) {
+		t.Errorf("result = %s, want %s", got, want)
+	}
+}
+
+// The test vectors of RFC 7396 appendix A
+func TestMergePatchRFC7396(t *testing.T) {
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.patch, func(t *testing.T) {
+			got, err := MergePatch(tt.patch).Apply([]byte(tt.target))
+			if err != nil {
+				t.Fatalf("applying %s to %s: %v", tt.patch, tt.target, err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+// The test vectors of RFC 6902 appendix A
+func TestJSONPatchRFC6902(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc, patch string
+		want       string
+		err        error
+	}{
+		{"A.1 add object member",
+			`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`,
+			`{"baz":"qux","foo":"bar"}`, nil},
+		{"A.2 add array element",
+			`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			`{"foo":["bar","qux","baz"]}`, nil},
+		{"A.3 remove object member",
+			`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`,
+			`{"foo":"bar"}`, nil},
+		{"A.4 remove array element",
+			`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`,
+			`{"foo":["bar","baz"]}`, nil},
+		{"A.5 replace value",
+			`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			`{"baz":"boo","foo":"bar"}`, nil},
+		{"A.6 move value",
+			`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`, nil},
+		{"A.7 move array element",
+			`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			`{"foo":["all","cows","eat","grass"]}`, nil},
+		{"A.8 test value success",
+			`{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`, nil},
+		{"A.9 test value error",
+			`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`,
+			"", ErrTestFailed},
+		{"A.10 add nested member object",
+			`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			`{"foo":"bar","child":{"grandchild":{}}}`, nil},
+		{"A.11 ignore unrecognized elements",
+			`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+			`{"foo":"bar","baz":"qux"}`, nil},
+		{"A.12 add to nonexistent target",
+			`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			"", ErrInvalidPatch},
+		{"A.14 ~ escape ordering",
+			`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`,
+			`{"/":9,"~1":10}`, nil},
+		{"A.15 comparing strings and numbers",
+			`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":"10"}]`,
+			"", ErrTestFailed},
+		{"A.16 add array value",
+			`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			`{"foo":["bar",["abc","def"]]}`, nil},
+		{"~1 escape",
+			`{"a/b":1}`, `[{"op":"replace","path":"/a~1b","value":2}]`,
+			`{"a/b":2}`, nil},
+		{"copy value",
+			`{"foo":{"bar":[1]}}`, `[{"op":"copy","from":"/foo/bar","path":"/baz"},{"op":"add","path":"/baz/-","value":2}]`,
+			`{"foo":{"bar":[1]},"baz":[1,2]}`, nil},
+		{"test numbers by value",
+			`{"n":1}`, `[{"op":"test","path":"/n","value":1.0}]`,
+			`{"n":1}`, nil},
+		{"move into own child",
+			`{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/c"}]`,
+			"", ErrInvalidPatch},
+		{"index past the end",
+			`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/2","value":"qux"}]`,
+			"", ErrInvalidPatch},
+		{"remove past the end",
+			`{"foo":["bar"]}`, `[{"op":"remove","path":"/foo/1"}]`,
+			"", ErrInvalidPatch},
+		{"replace in an empty array",
+			`{"foo":[]}`, `[{"op":"replace","path":"/foo/0","value":1}]`,
+			"", ErrInvalidPatch},
+		{"negative index",
+			`{"foo":["bar"]}`, `[{"op":"test","path":"/foo/-1","value":"bar"}]`,
+			"", ErrInvalidPatch},
+		{"leading zero index",
+			`{"foo":["bar","baz"]}`, `[{"op":"test","path":"/foo/01","value":"baz"}]`,
+			"", ErrInvalidPatch},
+		{"overflowing index",
+			`{"foo":["bar"]}`, `[{"op":"remove","path":"/foo/99999999999999999999"}]`,
+			"", ErrInvalidPatch},
+		{"- outside add",
+			`{"foo":["bar"]}`, `[{"op":"remove","path":"/foo/-"}]`,
+			"", ErrInvalidPatch},
+		{"atomic on failure",
+			`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":1},{"op":"test","path":"/foo","value":"qux"}]`,
+			"", ErrTestFailed},
+		{"unknown op",
+			`{}`, `[{"op":"merge","path":"/a","value":1}]`,
+			"", ErrInvalidPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ops JSONPatch
+			if err := json.Unmarshal([]byte(tt.patch), &ops); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ops.Apply([]byte(tt.doc))
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		contentType, body string
+		want              interface{}
+		err               error
+	}{
+		{"application/merge-patch+json", `{"a":1}`, MergePatch(nil), nil},
+		{"application/json; charset=utf-8", `{"a":1}`, MergePatch(nil), nil},
+		{"Application/JSON-Patch+JSON", `[{"op":"remove","path":"/a"}]`, JSONPatch(nil), nil},
+		{"application/merge-patch+json", `{"a":`, nil, ErrInvalidPatch},
+		{"application/json-patch+json", `{"op":"remove"}`, nil, ErrInvalidPatch},
+		{"text/plain", `{}`, nil, ErrUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		p, err := Parse(tt.contentType, []byte(tt.body))
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q, %s) error = %v, want %v", tt.contentType, tt.body, err, tt.err)
+			continue
+		}
+		switch tt.want.(type) {
+		case MergePatch:
+			if _, ok := p.(MergePatch); !ok {
+				t.Errorf("Parse(%q) = %T, want a merge patch", tt.contentType, p)
+			}
+		case JSONPatch:
+			if _, ok := p.(JSONPatch); !ok {
+				t.Errorf("Parse(%q) = %T, want a JSON patch", tt.contentType, p)
+			}
+		}
+	}
+}
+
+func TestApplyTo(t *testing.T) {
+	type profile struct {
+		Name string `json:"name"`
+		Bio  string `json:"bio"`
+	}
+	target := profile{Name: "alice", Bio: "hi"}
+	if err := ApplyTo(MergePatch(`{"bio":null}`), &target); err != nil || target != (profile{Name: "alice"}) {
+		t.Errorf("removing bio = %+v, %v", target, err)
+	}
+	if err := ApplyTo(MergePatch(`{"admin":true}`), &target); !errors.Is(err, ErrInvalidPatch) {
+		t.Errorf("unknown member error = %v, want %v", err, ErrInvalidPatch)
+	}
+}
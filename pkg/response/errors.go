@@ -0,0 +1,49 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPError is an error that carries the HTTP status it should be reported with
+type HTTPError struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+// Error implements the error interface
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the underlying error
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// NewError creates an error reported with the given status and message
+func NewError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+// WrapError attaches a status and client-facing message to an underlying error
+func WrapError(status int, message string, err error) *HTTPError {
+	return &HTTPError{Status: status, Message: message, Err: err}
+}
+
+// FromError sends an error response for err. HTTPErrors keep their status
+// and message; anything else is reported as an internal server error.
+func FromError(c *gin.Context, err error) {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		ErrorResponse(c, httpErr.Status, httpErr.Message)
+		return
+	}
+	ErrorResponse(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,282 @@
+package routing
+
+import (
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/jinzhu/inflection"
+)
+
+// ConventionContext describes the handler whose methods a convention maps
+type ConventionContext struct {
+	HandlerName string // e.g. "UserHandler"
+	BasePath    string // e.g. "/users"
+
+	// Resource is the singular resource name, e.g. "User" for /users
+	Resource string
+	// Parent is the resource the handler is nested under, e.g. "Post" for /posts/:postId/comments
+	Parent string
+	// Singletons are sub-resources that exist once per resource, e.g. "Profile"
+	Singletons []string
+}
+
+// ResourceName derives the singular resource name from a base path,
+// e.g. "/users" -> "User", "/user-groups" -> "UserGroup"
+func ResourceName(basePath string) string {
+	segments := strings.Split(strings.Trim(basePath, "/"), "/")
+	last := segments[len(segments)-1]
+	if last == "" || last[0] == ':' || last[0] == '*' {
+		return ""
+	}
+
+	var name strings.Builder
+	for _, word := range strings.FieldsFunc(last, func(r rune) bool { return r == '-' || r == '_' }) {
+		name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return inflection.Singular(name.String())
+}
+
+// RouteConvention maps a handler method name to an HTTP method and a path
+// relative to the handler's base path
+type RouteConvention interface {
+	Route(ctx ConventionContext, methodName string) (method, path string, ok bool)
+}
+
+// ConventionProvider is implemented by handlers that choose their own convention
+type ConventionProvider interface {
+	RouteConvention() RouteConvention
+}
+
+// ConventionFunc adapts a plain function to RouteConvention
+type ConventionFunc func(ctx ConventionContext, methodName string) (method, path string, ok bool)
+
+// Route implements RouteConvention
+func (f ConventionFunc) Route(ctx ConventionContext, methodName string) (string, string, bool) {
+	return f(ctx, methodName)
+}
+
+// restVerbs maps REST verb prefixes to HTTP methods
+var restVerbs = []struct {
+	prefix string
+	method string
+}{
+	{"Create", http.MethodPost},
+	{"Get", http.MethodGet},
+	{"Update", http.MethodPut},
+	{"Patch", http.MethodPatch},
+	{"Delete", http.MethodDelete},
+}
+
+// batchActions maps batch method prefixes to collection action paths.
+// gin can't route a literal colon, so Google-style /users:batchCreate
+// is served as /users/batch-create.
+var batchActions = []struct {
+	prefix string
+	path   string
+}{
+	{"BatchCreate", "/batch-create"},
+	{"BatchUpdate", "/batch-update"},
+	{"BatchDelete", "/batch-delete"},
+}
+
+// RESTConvention is the default resource-style convention:
+//
+//	CreateUser        -> POST   /
+//	GetAllUsers       -> GET    /
+//	GetUser           -> GET    /:id
+//	UpdateUser        -> PUT    /:id
+//	PatchUser         -> PATCH  /:id
+//	DeleteUser        -> DELETE /:id
+//	BatchCreateUsers  -> POST   /batch-create (also BatchUpdate*, BatchDelete*)
+//
+// Method names of the form <Verb><Resource><SubResource> address nested resources:
+//
+//	GetUserPosts      -> GET    /:id/posts
+//	CreateUserPost    -> POST   /:id/posts
+//	DeletePostComment -> DELETE /:id/comments/:commentId
+//	GetUserProfile    -> GET    /:id/profile   (when "Profile" is a singleton)
+//
+// Names qualified with "By", such as GetPostsByAuthor or GetUserByEmail,
+// don't say which path they address, so they aren't mapped and need a
+// declared route.
+type RESTConvention struct{}
+
+// Route implements RouteConvention
+func (RESTConvention) Route(ctx ConventionContext, methodName string) (string, string, bool) {
+	if hasQualifier(methodName) {
+		return "", "", false
+	}
+
+	// Prefixes only match whole words, so Getter or Deleted aren't routes
+	for _, action := range batchActions {
+		if hasWordPrefix(methodName, action.prefix) {
+			return http.MethodPost, action.path, true
+		}
+	}
+
+	if hasWordPrefix(methodName, "GetAll") {
+		return http.MethodGet, "", true
+	}
+
+	for _, verb := range restVerbs {
+		if !hasWordPrefix(methodName, verb.prefix) {
+			continue
+		}
+
+		if path, nested := nestedPath(ctx, verb.prefix, methodName[len(verb.prefix):]); nested {
+			return verb.method, path, true
+		}
+		if verb.method == http.MethodPost {
+			return verb.method, "", true
+		}
+		return verb.method, "/:id", true
+	}
+
+	// Not a route method
+	return "", "", false
+}
+
+// nestedPath derives a sub-resource path from the part of a method name
+// after the verb, e.g. "UserPosts" on the User resource -> "/:id/posts"
+func nestedPath(ctx ConventionContext, verb, name string) (string, bool) {
+	// <Parent><Resource> addresses the handler's own resource under its parent
+	if ctx.Parent != "" && hasWordPrefix(name, ctx.Parent) {
+		if own := name[len(ctx.Parent):]; own == ctx.Resource {
+			if verb == "Create" {
+				return "", true
+			}
+			return "/:id", true
+		} else if own == inflection.Plural(ctx.Resource) {
+			return "", true
+		}
+	}
+
+	if ctx.Resource == "" || !hasWordPrefix(name, ctx.Resource) {
+		return "", false
+	}
+	sub := name[len(ctx.Resource):]
+	if sub == "" {
+		return "", false
+	}
+
+	for _, singleton := range ctx.Singletons {
+		if sub == singleton {
+			return "/:id/" + kebabCase(sub), true
+		}
+	}
+
+	singular := inflection.Singular(sub)
+	collection := "/:id/" + kebabCase(inflection.Plural(singular))
+	if singular != sub || verb == "Create" {
+		// Plural sub-resources and creation address the collection
+		return collection, true
+	}
+	return collection + "/:" + lowerFirst(singular) + "Id", true
+}
+
+// RPCConvention maps every method to POST /<methodName>, e.g.
+// CreateUser -> POST /createUser
+type RPCConvention struct{}
+
+// Route implements RouteConvention
+func (RPCConvention) Route(_ ConventionContext, methodName string) (string, string, bool) {
+	return http.MethodPost, "/" + lowerFirst(methodName), true
+}
+
+// PrefixRule maps methods starting with Prefix to Method and Path
+type PrefixRule struct {
+	Prefix string
+	Method string
+	Path   string
+}
+
+// PrefixConvention maps methods through a table of prefix rules.
+// Rules are tried in order and a prefix only matches at a word boundary,
+// so "List" matches ListUsers but not Listen.
+type PrefixConvention struct {
+	Rules []PrefixRule
+}
+
+// NewPrefixConvention creates a prefix convention from rules
+func NewPrefixConvention(rules ...PrefixRule) PrefixConvention {
+	return PrefixConvention{Rules: rules}
+}
+
+// CommonPrefixRules covers verbs the REST convention doesn't know about
+var CommonPrefixRules = []PrefixRule{
+	{Prefix: "List", Method: http.MethodGet, Path: ""},
+	{Prefix: "Search", Method: http.MethodGet, Path: "/search"},
+	{Prefix: "Patch", Method: http.MethodPatch, Path: "/:id"},
+	{Prefix: "Archive", Method: http.MethodPost, Path: "/:id/archive"},
+}
+
+// Route implements RouteConvention
+func (p PrefixConvention) Route(_ ConventionContext, methodName string) (string, string, bool) {
+	for _, rule := range p.Rules {
+		if hasWordPrefix(methodName, rule.Prefix) {
+			return rule.Method, rule.Path, true
+		}
+	}
+	return "", "", false
+}
+
+// chainConvention tries several conventions in order
+type chainConvention []RouteConvention
+
+// Chain composes conventions; the first one that maps a method wins
+func Chain(conventions ...RouteConvention) RouteConvention {
+	return chainConvention(conventions)
+}
+
+// Route implements RouteConvention
+func (c chainConvention) Route(ctx ConventionContext, methodName string) (string, string, bool) {
+	for _, convention := range c {
+		if method, path, ok := convention.Route(ctx, methodName); ok {
+			return method, path, true
+		}
+	}
+	return "", "", false
+}
+
+// hasWordPrefix reports whether name starts with prefix followed by a new word
+func hasWordPrefix(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	rest := name[len(prefix):]
+	return rest == "" || unicode.IsUpper(rune(rest[0]))
+}
+
+// hasQualifier reports whether name contains the word "By", as in GetPostsByAuthor
+func hasQualifier(name string) bool {
+	for i := 0; i+2 <= len(name); i++ {
+		if name[i:i+2] == "By" && (i+2 == len(name) || unicode.IsUpper(rune(name[i+2]))) {
+			return true
+		}
+	}
+	return false
+}
+
+// kebabCase converts a CamelCase name to kebab-case, e.g. UserGroups -> user-groups
+func kebabCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// lowerFirst lowercases the first letter of s
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
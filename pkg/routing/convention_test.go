@@ -0,0 +1,209 @@
+package routing
+
+import (
+	"net/http"
+	"testing"
+)
+
+// conventionCase is a method name and the route a convention should map it to
+type conventionCase struct {
+	methodName   string
+	method, path string
+	ok           bool
+}
+
+// checkConvention maps every case with convention on ctx
+func checkConvention(t *testing.T, convention RouteConvention, ctx ConventionContext, cases []conventionCase) {
+	t.Helper()
+	for _, tt := range cases {
+		method, path, ok := convention.Route(ctx, tt.methodName)
+		if method != tt.method || path != tt.path || ok != tt.ok {
+			t.Errorf("Route(%s) = %s %q %v, want %s %q %v",
+				tt.methodName, method, path, ok, tt.method, tt.path, tt.ok)
+		}
+	}
+}
+
+// usersContext is the context of a handler mounted at /users
+var usersContext = ConventionContext{
+	HandlerName: "UserHandler",
+	BasePath:    "/users",
+	Resource:    "User",
+	Singletons:  []string{"Profile"},
+}
+
+func TestRESTConvention(t *testing.T) {
+	checkConvention(t, RESTConvention{}, usersContext, []conventionCase{
+		{"CreateUser", http.MethodPost, "", true},
+		{"GetAllUsers", http.MethodGet, "", true},
+		{"GetUser", http.MethodGet, "/:id", true},
+		{"UpdateUser", http.MethodPut, "/:id", true},
+		{"PatchUser", http.MethodPatch, "/:id", true},
+		{"DeleteUser", http.MethodDelete, "/:id", true},
+
+		// Batch actions on the collection
+		{"BatchCreateUsers", http.MethodPost, "/batch-create", true},
+		{"BatchUpdateUsers", http.MethodPost, "/batch-update", true},
+		{"BatchDeleteUsers", http.MethodPost, "/batch-delete", true},
+
+		// Singletons exist once per resource
+		{"GetUserProfile", http.MethodGet, "/:id/profile", true},
+		{"UpdateUserProfile", http.MethodPut, "/:id/profile", true},
+		{"DeleteUserProfile", http.MethodDelete, "/:id/profile", true},
+
+		// Not route methods
+		{"Routes", "", "", false},
+		{"Login", "", "", false},
+		{"Getter", "", "", false},
+		{"Deleted", "", "", false},
+		{"BatchCreated", "", "", false},
+		{"", "", "", false},
+	})
+}
+
+func TestRESTConventionWithoutResource(t *testing.T) {
+	// Handlers mounted at a param path have no resource name to nest under
+	checkConvention(t, RESTConvention{}, ConventionContext{BasePath: "/:tenant"}, []conventionCase{
+		{"GetAllowedThings", http.MethodGet, "/:id", true},
+		{"GetAll", http.MethodGet, "", true},
+		{"CreateThing", http.MethodPost, "", true},
+	})
+}
+
+func TestRPCConvention(t *testing.T) {
+	checkConvention(t, RPCConvention{}, usersContext, []conventionCase{
+		{"CreateUser", http.MethodPost, "/createUser", true},
+		{"GetUser", http.MethodPost, "/getUser", true},
+		{"Login", http.MethodPost, "/login", true},
+	})
+}
+
+func TestPrefixConvention(t *testing.T) {
+	checkConvention(t, NewPrefixConvention(CommonPrefixRules...), usersContext, []conventionCase{
+		{"ListUsers", http.MethodGet, "", true},
+		{"List", http.MethodGet, "", true},
+		{"SearchUsers", http.MethodGet, "/search", true},
+		{"PatchUser", http.MethodPatch, "/:id", true},
+		{"ArchiveUser", http.MethodPost, "/:id/archive", true},
+
+		// Prefixes only match whole words
+		{"Listen", "", "", false},
+		{"Searchable", "", "", false},
+		{"GetUser", "", "", false},
+	})
+
+	// Rules are tried in order
+	ordered := NewPrefixConvention(
+		PrefixRule{Prefix: "Get", Method: http.MethodGet, Path: "/:id"},
+		PrefixRule{Prefix: "Get", Method: http.MethodGet, Path: "/shadowed"},
+	)
+	checkConvention(t, ordered, usersContext, []conventionCase{{"GetUser", http.MethodGet, "/:id", true}})
+
+	checkConvention(t, NewPrefixConvention(), usersContext, []conventionCase{{"ListUsers", "", "", false}})
+}
+
+func TestChain(t *testing.T) {
+	chain := Chain(NewPrefixConvention(CommonPrefixRules...), RESTConvention{})
+	checkConvention(t, chain, usersContext, []conventionCase{
+		{"ListUsers", http.MethodGet, "", true},                // first convention
+		{"GetUser", http.MethodGet, "/:id", true},              // falls through to REST
+		{"PatchUser", http.MethodPatch, "/:id", true},          // both match; the first wins
+		{"ArchiveUser", http.MethodPost, "/:id/archive", true}, // REST doesn't know Archive
+		{"Helper", "", "", false},                              // no convention matches
+	})
+
+	// A catch-all convention shadows the ones after it
+	checkConvention(t, Chain(RPCConvention{}, RESTConvention{}), usersContext, []conventionCase{
+		{"GetUser", http.MethodPost, "/getUser", true},
+	})
+	checkConvention(t, Chain(), usersContext, []conventionCase{{"GetUser", "", "", false}})
+
+	// ConventionFunc adapts plain functions
+	only := ConventionFunc(func(_ ConventionContext, methodName string) (string, string, bool) {
+		return http.MethodGet, "/only", methodName == "Only"
+	})
+	checkConvention(t, Chain(only), usersContext, []conventionCase{
+		{"Only", http.MethodGet, "/only", true},
+		{"Other", "", "", false},
+	})
+}
+
+func TestResourceName(t *testing.T) {
+	for basePath, want := range map[string]string{
+		"/users":          "User",
+		"/user-groups":    "UserGroup",
+		"/order_items":    "OrderItem",
+		"/posts/:id/tags": "Tag",
+		"/people":         "Person",
+		"/:tenant":        "",
+		"/":               "",
+	} {
+		if got := ResourceName(basePath); got != want {
+			t.Errorf("ResourceName(%q) = %q, want %q", basePath, got, want)
+		}
+	}
+}
+
+func TestRESTConventionNestedResources(t *testing.T) {
+	t.Run("sub-resources", func(t *testing.T) {
+		checkConvention(t, RESTConvention{}, ConventionContext{BasePath: "/users", Resource: "User"}, []conventionCase{
+			{"GetUserPosts", http.MethodGet, "/:id/posts", true},
+			{"CreateUserPost", http.MethodPost, "/:id/posts", true},
+			{"GetUserPost", http.MethodGet, "/:id/posts/:postId", true},
+			{"UpdateUserPost", http.MethodPut, "/:id/posts/:postId", true},
+			{"DeleteUserPost", http.MethodDelete, "/:id/posts/:postId", true},
+			{"GetUserGroupMembers", http.MethodGet, "/:id/group-members", true},
+			{"GetUserCategories", http.MethodGet, "/:id/categories", true},
+
+			// Without a singleton declaration, Profile is one of many
+			{"GetUserProfile", http.MethodGet, "/:id/profiles/:profileId", true},
+
+			// The resource must be a whole word
+			{"GetUsername", http.MethodGet, "/:id", true},
+		})
+		checkConvention(t, RESTConvention{}, ConventionContext{BasePath: "/posts", Resource: "Post"}, []conventionCase{
+			{"GetPostComments", http.MethodGet, "/:id/comments", true},
+			{"DeletePostComment", http.MethodDelete, "/:id/comments/:commentId", true},
+		})
+	})
+
+	t.Run("singletons", func(t *testing.T) {
+		ctx := ConventionContext{BasePath: "/users", Resource: "User", Singletons: []string{"Profile", "AvatarImage"}}
+		checkConvention(t, RESTConvention{}, ctx, []conventionCase{
+			{"GetUserProfile", http.MethodGet, "/:id/profile", true},
+			{"CreateUserProfile", http.MethodPost, "/:id/profile", true},
+			{"PatchUserProfile", http.MethodPatch, "/:id/profile", true},
+			{"GetUserAvatarImage", http.MethodGet, "/:id/avatar-image", true},
+			// Only the declared name is a singleton, not its plural
+			{"GetUserProfiles", http.MethodGet, "/:id/profiles", true},
+		})
+	})
+
+	t.Run("under a parent", func(t *testing.T) {
+		// CommentHandler mounted with WithParent("/posts", "postId")
+		ctx := ConventionContext{BasePath: "/posts/:postId/comments", Resource: "Comment", Parent: "Post"}
+		checkConvention(t, RESTConvention{}, ctx, []conventionCase{
+			{"GetPostComments", http.MethodGet, "", true},
+			{"CreatePostComment", http.MethodPost, "", true},
+			{"GetPostComment", http.MethodGet, "/:id", true},
+			{"DeletePostComment", http.MethodDelete, "/:id", true},
+			{"GetAllComments", http.MethodGet, "", true},
+			{"GetComment", http.MethodGet, "/:id", true},
+			{"GetCommentReplies", http.MethodGet, "/:id/replies", true},
+		})
+	})
+
+	t.Run("ambiguous names", func(t *testing.T) {
+		// Qualified names need a declared route instead of guessing /:id
+		checkConvention(t, RESTConvention{}, ConventionContext{BasePath: "/posts", Resource: "Post"}, []conventionCase{
+			{"GetPostsByAuthor", "", "", false},
+			{"GetAllPostsByAuthor", "", "", false},
+			{"GetPostBy", "", "", false},
+			{"DeletePostsByIDs", "", "", false},
+			{"BatchDeletePostsByAuthor", "", "", false},
+			// "By" inside a word isn't a qualifier
+			{"GetPostBylines", http.MethodGet, "/:id/bylines", true},
+			{"GetPostBypass", http.MethodGet, "/:id/bypasses/:bypassId", true},
+		})
+	})
+}
@@ -0,0 +1,12 @@
+package routing
+
+import "time"
+
+// Deprecation marks a route or API version as deprecated.
+// Deprecated routes answer with Deprecation, Sunset and Link headers.
+type Deprecation struct {
+	Since     time.Time // when the route was deprecated; zero sends "Deprecation: true"
+	Sunset    time.Time // when the route stops being served; zero omits the Sunset header
+	Link      string    // documentation about the deprecation (rel="deprecation")
+	Successor string    // route that replaces this one (rel="successor-version")
+}
@@ -0,0 +1,137 @@
+package routing
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"study-go-controller/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// paramKeyPrefix namespaces parsed path params in the gin context
+const paramKeyPrefix = "routing.param."
+
+// uuidPattern matches the canonical 8-4-4-4-12 hex form
+const uuidPattern = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+
+// ParamType describes the values a path parameter accepts
+type ParamType struct {
+	Name        string // e.g. "uint"
+	Description string // used in error messages, e.g. "an unsigned integer"
+	Pattern     string // regular expression string values must match, if any
+
+	parse func(value string) (interface{}, error)
+}
+
+// Built-in parameter types
+var (
+	Uint = ParamType{Name: "uint", Description: "an unsigned integer", parse: func(value string) (interface{}, error) {
+		n, err := strconv.ParseUint(value, 10, 32)
+		return uint(n), err
+	}}
+	Int = ParamType{Name: "int", Description: "an integer", parse: func(value string) (interface{}, error) {
+		return strconv.Atoi(value)
+	}}
+	UUID   = patternType("uuid", "a UUID", uuidPattern)
+	String = ParamType{Name: "string", Description: "a string", parse: func(value string) (interface{}, error) {
+		return value, nil
+	}}
+)
+
+// Pattern accepts string values matching a regular expression, e.g. Pattern(`^[a-z0-9-]+$`) for slugs
+func Pattern(expr string) ParamType {
+	return patternType("pattern", "a value matching "+expr, expr)
+}
+
+// patternType builds a string type validated by a regular expression
+func patternType(name, description, expr string) ParamType {
+	re := regexp.MustCompile(expr)
+	return ParamType{
+		Name:        name,
+		Description: description,
+		Pattern:     expr,
+		parse: func(value string) (interface{}, error) {
+			if !re.MatchString(value) {
+				return nil, fmt.Errorf("%q does not match %s", value, expr)
+			}
+			return value, nil
+		},
+	}
+}
+
+// Parse validates a raw path value and returns it as the type's Go value
+func (t ParamType) Parse(value string) (interface{}, error) {
+	if t.parse == nil {
+		return value, nil
+	}
+	return t.parse(value)
+}
+
+// DefaultParamType is used for params without a declared type:
+// id, <name>Id and <name>_id are unsigned integers, anything else a string
+func DefaultParamType(name string) ParamType {
+	if name == "id" || strings.HasSuffix(name, "Id") || strings.HasSuffix(name, "_id") {
+		return Uint
+	}
+	return String
+}
+
+// ValidateParams parses path params before the handler runs, responding
+// 400 on the first invalid one; parsed values are read with UintParam etc.
+func ValidateParams(types map[string]ParamType) gin.HandlerFunc {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return func(c *gin.Context) {
+		for _, name := range names {
+			paramType := types[name]
+			value, err := paramType.Parse(c.Param(name))
+			if err != nil {
+				response.ErrorResponse(c, http.StatusBadRequest,
+					fmt.Sprintf("Invalid path parameter '%s': must be %s", name, paramType.Description))
+				c.Abort()
+				return
+			}
+			c.Set(paramKeyPrefix+name, value)
+		}
+		c.Next()
+	}
+}
+
+// UintParam returns a path param validated as Uint, or 0 if it isn't one
+func UintParam(c *gin.Context, name string) uint {
+	value, _ := param(c, name, Uint).(uint)
+	return value
+}
+
+// IntParam returns a path param validated as Int, or 0 if it isn't one
+func IntParam(c *gin.Context, name string) int {
+	value, _ := param(c, name, Int).(int)
+	return value
+}
+
+// StringParam returns a path param validated as a string type (String, UUID, Pattern)
+func StringParam(c *gin.Context, name string) string {
+	value, _ := param(c, name, String).(string)
+	return value
+}
+
+// param returns the value parsed by ValidateParams, parsing it with
+// fallback when the route wasn't registered through AutoRouter
+func param(c *gin.Context, name string, fallback ParamType) interface{} {
+	if value, ok := c.Get(paramKeyPrefix + name); ok {
+		return value
+	}
+	value, err := fallback.Parse(c.Param(name))
+	if err != nil {
+		return nil
+	}
+	return value
+}
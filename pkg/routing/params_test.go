@@ -0,0 +1,95 @@
+package routing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// paramRouter serves path with ValidateParams(types), echoing the parsed values
+func paramRouter(path string, types map[string]ParamType) *gin.Engine {
+	router := gin.New()
+	router.GET(path, ValidateParams(types), func(c *gin.Context) {
+		c.String(http.StatusOK, "%d %d %s", UintParam(c, "id"), IntParam(c, "offset"), StringParam(c, "key"))
+	})
+	return router
+}
+
+func TestValidateParams(t *testing.T) {
+	types := map[string]ParamType{"id": Uint, "offset": Int, "key": UUID}
+	router := paramRouter("/items/:id/:offset/:key", types)
+	const key = "123e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{"valid", "/items/42/-7/" + key, http.StatusOK, "42 -7 " + key},
+		{"largest uint", "/items/4294967295/0/" + key, http.StatusOK, "4294967295 0 " + key},
+		{"invalid uint", "/items/abc/0/" + key, http.StatusBadRequest, ""},
+		{"negative uint", "/items/-1/0/" + key, http.StatusBadRequest, ""},
+		{"overflowing uint", "/items/4294967296/0/" + key, http.StatusBadRequest, ""},
+		{"invalid int", "/items/1/seven/" + key, http.StatusBadRequest, ""},
+		{"overflowing int", "/items/1/99999999999999999999/" + key, http.StatusBadRequest, ""},
+		{"invalid uuid", "/items/1/0/not-a-uuid", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if recorder.Code != tt.status {
+				t.Fatalf("GET %s = %d %s, want %d", tt.path, recorder.Code, recorder.Body, tt.status)
+			}
+			if tt.body != "" && recorder.Body.String() != tt.body {
+				t.Errorf("GET %s = %q, want %q", tt.path, recorder.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestValidateParamsRejectsMissingParams(t *testing.T) {
+	// The route has no :id, so the declared param is empty
+	router := paramRouter("/items", map[string]ParamType{"id": Uint})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("GET /items = %d %s, want 400", recorder.Code, recorder.Body)
+	}
+}
+
+func TestParamsWithoutValidation(t *testing.T) {
+	// Routes registered outside AutoRouter parse on demand, with zero values for invalid input
+	router := paramRouter("/items/:id/:offset/:key", nil)
+	tests := map[string]string{
+		"/items/42/3/abc":                    "42 3 abc",
+		"/items/4294967296/x/abc":            "0 0 abc",
+		"/items/-1/99999999999999999999/abc": "0 0 abc",
+	}
+	for path, want := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if got := recorder.Body.String(); recorder.Code != http.StatusOK || got != want {
+			t.Errorf("GET %s = %d %q, want 200 %q", path, recorder.Code, got, want)
+		}
+	}
+}
+
+func TestDefaultParamType(t *testing.T) {
+	for name, want := range map[string]string{"id": "uint", "postId": "uint", "post_id": "uint", "slug": "string"} {
+		if got := DefaultParamType(name).Name; got != want {
+			t.Errorf("DefaultParamType(%s) = %s, want %s", name, got, want)
+		}
+	}
+	if _, err := Pattern(`^[a-z-]+$`).Parse("Hello"); err == nil {
+		t.Error(`Pattern accepted "Hello"`)
+	}
+}
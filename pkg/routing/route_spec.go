@@ -0,0 +1,165 @@
+package routing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteSpec declares a route explicitly for a handler method
+type RouteSpec struct {
+	Method string
+	Path   string
+	Action string // handler method name the route dispatches to
+
+	// Middleware runs only for this route, after handler-level middleware
+	Middleware []gin.HandlerFunc
+
+	// Versioning: the API version the route belongs to (empty means the
+	// handler's version), its deprecation notice, and whether the spec
+	// removes an inherited route instead of adding one
+	Version     string
+	Deprecation *Deprecation
+	Remove      bool
+
+	// SuccessMessage is sent with successful responses of typed handler methods
+	SuccessMessage string
+	// SuccessStatus is the status of successful responses; zero means 201
+	// for POST routes of Create* methods and 200 otherwise
+	SuccessStatus int
+
+	// Params overrides the type of path params, see DefaultParamType
+	Params map[string]ParamType
+
+	// Documentation metadata used for the OpenAPI document
+	Summary  string
+	Request  interface{} // request body or query prototype, e.g. dto.CreateUserRequest{}
+	Response interface{} // response data prototype, e.g. dto.UserResponse{}
+}
+
+// RouteProvider is implemented by handlers that declare their routes explicitly.
+// Methods without a declaration fall back to the naming convention.
+type RouteProvider interface {
+	Routes() []RouteSpec
+}
+
+// Action attaches metadata to a method that is still routed by convention
+func Action(action string) RouteSpec {
+	return RouteSpec{Action: action}
+}
+
+// BindingProvider is implemented by code generated with cmd/routegen: it maps
+// method names to bindings that call the method on any instance of the
+// handler type, so AutoRouter can skip reflection. The receiver is unused;
+// AutoRouter reads the table once per handler type.
+type BindingProvider interface {
+	RouteBindings() map[string]Binding
+}
+
+// Binding calls a route method on a handler instance, e.g. the one built
+// for the current request. Exactly one of Gin and Typed is set.
+type Binding struct {
+	// Gin calls a func(*gin.Context) method
+	Gin func(handler interface{}, c *gin.Context)
+
+	// Typed calls a func(context.Context[, *Request]) ([*Response, ]error)
+	// method with the request NewRequest allocated, which AutoRouter binds
+	// and validates first; NewRequest is nil for methods without a request
+	Typed      func(handler interface{}, ctx context.Context, req interface{}) (interface{}, error)
+	NewRequest func() interface{}
+}
+
+// MiddlewareProvider is implemented by handlers that apply middleware to all of their routes
+type MiddlewareProvider interface {
+	Middleware() []gin.HandlerFunc
+}
+
+// Remove drops a route inherited from the base API version
+func Remove(method, path string) RouteSpec {
+	return RouteSpec{Method: method, Path: path, Remove: true}
+}
+
+// GET declares a GET route for the given handler method
+func GET(path, action string) RouteSpec {
+	return RouteSpec{Method: http.MethodGet, Path: path, Action: action}
+}
+
+// POST declares a POST route for the given handler method
+func POST(path, action string) RouteSpec {
+	return RouteSpec{Method: http.MethodPost, Path: path, Action: action}
+}
+
+// PUT declares a PUT route for the given handler method
+func PUT(path, action string) RouteSpec {
+	return RouteSpec{Method: http.MethodPut, Path: path, Action: action}
+}
+
+// PATCH declares a PATCH route for the given handler method
+func PATCH(path, action string) RouteSpec {
+	return RouteSpec{Method: http.MethodPatch, Path: path, Action: action}
+}
+
+// DELETE declares a DELETE route for the given handler method
+func DELETE(path, action string) RouteSpec {
+	return RouteSpec{Method: http.MethodDelete, Path: path, Action: action}
+}
+
+// Describe sets the operation summary
+func (s RouteSpec) Describe(summary string) RouteSpec {
+	s.Summary = summary
+	return s
+}
+
+// Accepts sets the request prototype
+func (s RouteSpec) Accepts(request interface{}) RouteSpec {
+	s.Request = request
+	return s
+}
+
+// Returns sets the response data prototype
+func (s RouteSpec) Returns(response interface{}) RouteSpec {
+	s.Response = response
+	return s
+}
+
+// WithMessage sets the message sent with successful responses
+func (s RouteSpec) WithMessage(message string) RouteSpec {
+	s.SuccessMessage = message
+	return s
+}
+
+// WithStatus sets the status of successful responses
+func (s RouteSpec) WithStatus(status int) RouteSpec {
+	s.SuccessStatus = status
+	return s
+}
+
+// Use appends middleware to the route's chain
+func (s RouteSpec) Use(middleware ...gin.HandlerFunc) RouteSpec {
+	s.Middleware = append(append([]gin.HandlerFunc{}, s.Middleware...), middleware...)
+	return s
+}
+
+// ForVersion places the route in a specific API version
+func (s RouteSpec) ForVersion(version string) RouteSpec {
+	s.Version = version
+	return s
+}
+
+// Param declares the type of a path param, e.g. Param("slug", Pattern(`^[a-z0-9-]+$`))
+func (s RouteSpec) Param(name string, paramType ParamType) RouteSpec {
+	params := make(map[string]ParamType, len(s.Params)+1)
+	for existing, t := range s.Params {
+		params[existing] = t
+	}
+	params[name] = paramType
+	s.Params = params
+	return s
+}
+
+// Deprecate marks the route as deprecated
+func (s RouteSpec) Deprecate(deprecation Deprecation) RouteSpec {
+	s.Deprecation = &deprecation
+	return s
+}